@@ -2,15 +2,290 @@ package config
 
 import (
 	"encoding/json"
+	"fmt"
 	"log"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type Config struct {
 	TelegramToken string `json:"telegram_token"`
 	AdminID       int64  `json:"admin_id"`
 	StorageDir    string `json:"storage_dir"` // downloads/server
+	SocketPath    string `json:"socket_path"` // CLI socket, defaults to $TMPDIR/asb.sock
+
+	Webhook WebhookConfig `json:"webhook"`
+
+	// Additional users besides AdminID and their roles
+	Users []UserConfig `json:"users"`
+
+	Watchdog WatchdogConfig `json:"watchdog"`
+	Battery  BatteryConfig  `json:"battery"`
+	Disk     DiskConfig     `json:"disk"`
+
+	Processes []ProcessConfig  `json:"processes"`
+	Endpoints []EndpointConfig `json:"endpoints"`
+	Programs  []ProgramConfig  `json:"programs"`
+
+	// ServiceLogs maps service names to log files, overriding the log the
+	// service manager knows about
+	ServiceLogs map[string]string `json:"service_logs"`
+
+	Maintenance []MaintenanceWindow `json:"maintenance"`
+	Digest      DigestConfig        `json:"digest"`
+	Alerts      AlertsConfig        `json:"alerts"`
+	Heartbeat   HeartbeatConfig     `json:"heartbeat"`
+
+	Schedule  []TaskConfig    `json:"schedule"`
+	Dashboard DashboardConfig `json:"dashboard"`
+}
+
+// HeartbeatConfig sends healthchecks-style pings so an external monitor
+// notices when the device goes silent
+type HeartbeatConfig struct {
+	URL             string            `json:"url"`              // ping URL, empty disables the heartbeat
+	IntervalMinutes int               `json:"interval_minutes"` // default 5
+	TimeoutSeconds  int               `json:"timeout_seconds"`  // default 10
+	FailOnCritical  bool              `json:"fail_on_critical"` // send /fail while a check is critical
+	Jobs            map[string]string `json:"jobs"`             // scheduled job name -> its own ping URL
+}
+
+// DashboardConfig controls the /dashboard message
+type DashboardConfig struct {
+	RefreshSeconds int `json:"refresh_seconds"` // auto-refresh of pinned dashboards, default 60
+}
+
+// TaskConfig is a command run on a cron schedule. More tasks can be added
+// from chat with /schedule add.
+type TaskConfig struct {
+	Name           string `json:"name"` // task ID, also the heartbeat job name
+	Cron           string `json:"cron"` // e.g. "0 3 * * *" or "@daily"
+	Command        string `json:"command"`
+	Report         string `json:"report"`          // failures (default) or all
+	TimeoutMinutes int    `json:"timeout_minutes"` // default 10
+}
+
+// AlertsConfig routes watchdog alerts to sinks. Without routes all alerts
+// go to AdminID over Telegram.
+type AlertsConfig struct {
+	Routes     []AlertRoute      `json:"routes"`
+	Escalation *EscalationConfig `json:"escalation"`
+}
+
+// AlertRoute is a destination for alerts of at least MinSeverity
+type AlertRoute struct {
+	Name        string     `json:"name"`
+	Type        string     `json:"type"`         // telegram, webhook or smtp
+	MinSeverity string     `json:"min_severity"` // ok (default, includes recoveries), warning or critical
+	ChatID      int64      `json:"chat_id"`      // telegram: user or group chat
+	URL         string     `json:"url"`          // webhook: receives a JSON POST
+	SMTP        SMTPConfig `json:"smtp"`
+}
+
+// SMTPConfig is an email sink
+type SMTPConfig struct {
+	Host     string   `json:"host"`
+	Port     int      `json:"port"` // default 587
+	Username string   `json:"username"`
+	Password string   `json:"password"`
+	From     string   `json:"from"`
+	To       []string `json:"to"`
+}
+
+// EscalationConfig repeats unacknowledged alerts to a second recipient
+type EscalationConfig struct {
+	AfterMinutes int        `json:"after_minutes"` // default 15
+	MinSeverity  string     `json:"min_severity"`  // default critical
+	Route        AlertRoute `json:"route"`
+}
+
+// DigestConfig schedules the daily and weekly summary reports
+type DigestConfig struct {
+	Daily    bool   `json:"daily"`
+	Weekly   bool   `json:"weekly"`
+	Time     string `json:"time"`     // "09:00", default
+	Weekday  string `json:"weekday"`  // day of the weekly digest, default "mon"
+	Timezone string `json:"timezone"` // IANA name, default system timezone
+
+	location *time.Location
+	minute   int // minutes since midnight
+	weekday  time.Weekday
+}
+
+// Location returns the timezone of the digest and of the daily statistics
+func (d DigestConfig) Location() *time.Location {
+	if d.location == nil {
+		return time.Local
+	}
+	return d.location
+}
+
+// At returns the time of day the digest is sent
+func (d DigestConfig) At() (hour, minute int) {
+	return d.minute / 60, d.minute % 60
+}
+
+// Day returns the weekday of the weekly digest
+func (d DigestConfig) Day() time.Weekday {
+	return d.weekday
+}
+
+// parse validates the digest settings and fills the parsed fields
+func (d *DigestConfig) parse() error {
+	if d.Time == "" {
+		d.Time = "09:00"
+	}
+	if d.Weekday == "" {
+		d.Weekday = "mon"
+	}
+
+	var err error
+	if d.minute, err = parseClock(d.Time); err != nil {
+		return fmt.Errorf("invalid time: %v", err)
+	}
+	weekday, ok := weekdays[strings.ToLower(d.Weekday)]
+	if !ok {
+		return fmt.Errorf("unknown weekday %q", d.Weekday)
+	}
+	d.weekday = weekday
+
+	if d.Timezone != "" {
+		if d.location, err = time.LoadLocation(d.Timezone); err != nil {
+			return fmt.Errorf("invalid timezone: %v", err)
+		}
+	}
+	return nil
+}
+
+// EndpointConfig describes an HTTP(S) URL or a TCP host:port probed by the watchdog
+type EndpointConfig struct {
+	Name               string `json:"name"`
+	URL                string `json:"url"`           // HTTP(S) check
+	Address            string `json:"address"`       // TCP check, host:port
+	ExpectStatus       int    `json:"expect_status"` // default: any 2xx or 3xx after redirects
+	BodyContains       string `json:"body_contains"`
+	MaxLatencyMs       int    `json:"max_latency_ms"`  // slower is a warning, default 2000
+	TLSExpiryDays      int    `json:"tls_expiry_days"` // warn before certificate expiry, default 14
+	InsecureSkipVerify bool   `json:"insecure_skip_verify"`
+	TimeoutSeconds     int    `json:"timeout_seconds"`  // default 10
+	IntervalSeconds    int    `json:"interval_seconds"` // default 60
+}
+
+// ProcessConfig describes a process the watchdog keeps alive. It is found
+// by pidfile, by pgrep pattern or, if neither is set, by its command line.
+type ProcessConfig struct {
+	Name               string `json:"name"`
+	Pidfile            string `json:"pidfile"`
+	Pattern            string `json:"pattern"`
+	Command            string `json:"command"`               // start command, used for auto-restart
+	AutoRestart        bool   `json:"auto_restart"`          // requires command
+	MaxRestartsPerHour int    `json:"max_restarts_per_hour"` // default 3
+	BackoffSeconds     int    `json:"backoff_seconds"`       // first retry delay, doubles per attempt, default 10
+	IntervalSeconds    int    `json:"interval_seconds"`      // default 60
+}
+
+// ProgramConfig is a program ASB starts and supervises itself, since
+// Termux has no init system
+type ProgramConfig struct {
+	Name               string            `json:"name"`
+	Command            string            `json:"command"` // run with sh -c
+	Dir                string            `json:"dir"`     // working directory, default home
+	Env                map[string]string `json:"env"`
+	Restart            string            `json:"restart"`              // always (default), on-failure or never
+	Disabled           bool              `json:"disabled"`             // not started with ASB
+	LogFile            string            `json:"log_file"`             // default <storage_dir>/logs/<name>.log
+	LogMaxKB           int               `json:"log_max_kb"`           // rotate above this size, default 1024
+	LogBackups         int               `json:"log_backups"`          // rotated files kept, default 3
+	StopTimeoutSeconds int               `json:"stop_timeout_seconds"` // SIGKILL after this, default 10
+}
+
+// WatchdogConfig holds the health check scheduler settings
+type WatchdogConfig struct {
+	IntervalMinutes int `json:"interval_minutes"` // default interval for checks, default 10
+}
+
+// Interval returns the default check interval
+func (w WatchdogConfig) Interval() time.Duration {
+	return time.Duration(w.IntervalMinutes) * time.Minute
+}
+
+// DiskConfig holds the disk space alert thresholds
+type DiskConfig struct {
+	Paths           []string `json:"paths"`            // default: /data and ~/asb_files
+	WarningPercent  float64  `json:"warning_percent"`  // used space, default 85
+	CriticalPercent float64  `json:"critical_percent"` // used space, default 95
+	MinFreeMB       int64    `json:"min_free_mb"`      // critical below this, default 500
+	PredictHours    int      `json:"predict_hours"`    // warn when predicted full within, default 72
+	IntervalMinutes int      `json:"interval_minutes"` // default: watchdog interval
+}
+
+// BatteryConfig holds the battery alert thresholds in percent
+type BatteryConfig struct {
+	WarningPercent  float64 `json:"warning_percent"`  // default 20
+	CriticalPercent float64 `json:"critical_percent"` // default 10
+	ShutdownPercent float64 `json:"shutdown_percent"` // default 5
+	ChargedPercent  float64 `json:"charged_percent"`  // "unplug now" notice, default 80, -1 disables
+	Hysteresis      float64 `json:"hysteresis"`       // recovery margin, default 3
+
+	// Temperature thresholds in °C
+	TempWarning    float64 `json:"temp_warning"`          // default 40
+	TempCritical   float64 `json:"temp_critical"`         // default 45
+	TempHysteresis float64 `json:"temp_hysteresis"`       // default 2
+	TempCooldown   int     `json:"temp_cooldown_minutes"` // repeat interval while hot, default 30
+
+	// Shell commands run when the temperature becomes critical (e.g. pause
+	// jobs with `pkill -STOP -f myjob`) and when it is back to normal
+	OverheatCommands []string `json:"overheat_commands"`
+	CooldownCommands []string `json:"cooldown_commands"`
+}
+
+// Role controls which commands a user may run
+type Role string
+
+const (
+	RoleViewer   Role = "viewer"   // read-only status commands
+	RoleOperator Role = "operator" // may also manage services
+	RoleAdmin    Role = "admin"    // full access, including shell and reboot
+)
+
+// Level orders roles so that higher levels include lower ones
+func (r Role) Level() int {
+	switch r {
+	case RoleAdmin:
+		return 3
+	case RoleOperator:
+		return 2
+	case RoleViewer:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Allows reports whether r grants the permissions of required
+func (r Role) Allows(required Role) bool {
+	return r.Level() >= required.Level()
+}
+
+// UserConfig grants a Telegram user access with the given role
+type UserConfig struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	Role Role   `json:"role"`
+}
+
+// WebhookConfig switches update delivery from long polling to a webhook
+type WebhookConfig struct {
+	Enabled     bool   `json:"enabled"`
+	Listen      string `json:"listen"`       // local address, e.g. 127.0.0.1:8443
+	PublicURL   string `json:"public_url"`   // https URL Telegram posts to (proxy or tunnel)
+	SecretToken string `json:"secret_token"` // generated at startup if empty
+	CertFile    string `json:"cert_file"`    // optional, serve TLS directly
+	KeyFile     string `json:"key_file"`
 }
 
 func LoadConfig() *Config {
@@ -32,14 +307,460 @@ func LoadConfig() *Config {
 	if cfg.AdminID == 0 {
 		log.Fatal("admin_id is required in config.json")
 	}
+	if cfg.Webhook.Enabled {
+		if cfg.Webhook.PublicURL == "" {
+			log.Fatal("webhook.public_url is required when webhook is enabled")
+		}
+		if cfg.Webhook.Listen == "" {
+			cfg.Webhook.Listen = "127.0.0.1:8443"
+		}
+	}
+	if cfg.Watchdog.IntervalMinutes <= 0 {
+		cfg.Watchdog.IntervalMinutes = 10
+	}
+	applyBatteryDefaults(&cfg.Battery)
+	applyDiskDefaults(&cfg.Disk)
+	// Check names are shared by the built-in checks, processes and endpoints
+	names := map[string]bool{"battery": true, "disk": true}
+	applyProcessDefaults(cfg.Processes, names)
+	applyEndpointDefaults(cfg.Endpoints, names)
+	applyProgramDefaults(cfg.Programs)
+	for name, path := range cfg.ServiceLogs {
+		cfg.ServiceLogs[name] = expandHome(path)
+	}
+	for i := range cfg.Maintenance {
+		if err := cfg.Maintenance[i].parse(); err != nil {
+			log.Fatalf("maintenance[%d]: %v", i, err)
+		}
+	}
+	if err := cfg.Alerts.validate(); err != nil {
+		log.Fatalf("alerts: %v", err)
+	}
+	if cfg.Heartbeat.IntervalMinutes <= 0 {
+		cfg.Heartbeat.IntervalMinutes = 5
+	}
+	if cfg.Heartbeat.TimeoutSeconds <= 0 {
+		cfg.Heartbeat.TimeoutSeconds = 10
+	}
+	applyScheduleDefaults(cfg.Schedule)
+	if cfg.Dashboard.RefreshSeconds <= 0 {
+		cfg.Dashboard.RefreshSeconds = 60
+	} else if cfg.Dashboard.RefreshSeconds < 10 {
+		cfg.Dashboard.RefreshSeconds = 10 // stay well below Telegram's edit limits
+	}
+	if err := cfg.Digest.parse(); err != nil {
+		log.Fatalf("digest: %v", err)
+	}
 	if cfg.StorageDir == "" {
 		cfg.StorageDir = "downloads/server" // default value
 	}
+	cfg.SocketPath = expandHome(cfg.SocketPath)
+
+	for _, u := range cfg.Users {
+		if u.ID == 0 || u.Role.Level() == 0 {
+			log.Fatalf("users: invalid entry %+v (role must be viewer, operator or admin)", u)
+		}
+	}
 
 	setupDirectories(cfg.StorageDir)
 	return cfg
 }
 
+// ReadSocketPath returns socket_path from config.json without validating the
+// rest of the file, for the CLI client. It is empty when unset or unreadable.
+func ReadSocketPath() string {
+	data, err := os.ReadFile("config.json")
+	if err != nil {
+		return ""
+	}
+	var cfg struct {
+		SocketPath string `json:"socket_path"`
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return ""
+	}
+	return expandHome(cfg.SocketPath)
+}
+
+// RoleOf returns the role of a Telegram user, AdminID is always admin
+func (c *Config) RoleOf(userID int64) (Role, bool) {
+	if userID == c.AdminID {
+		return RoleAdmin, true
+	}
+	for _, u := range c.Users {
+		if u.ID == userID {
+			return u.Role, true
+		}
+	}
+	return "", false
+}
+
+// StatePath returns the location of a state file inside the storage dir.
+// Relative storage dirs are resolved against the home directory.
+func (c *Config) StatePath(name string) string {
+	dir := c.StorageDir
+	if !filepath.IsAbs(dir) {
+		home, _ := os.UserHomeDir()
+		dir = filepath.Join(home, dir)
+	}
+	return filepath.Join(dir, name)
+}
+
+// UserIDs returns AdminID followed by all configured users
+func (c *Config) UserIDs() []int64 {
+	ids := []int64{c.AdminID}
+	for _, u := range c.Users {
+		if u.ID != c.AdminID {
+			ids = append(ids, u.ID)
+		}
+	}
+	return ids
+}
+
+// validate checks the routes and fills defaults
+func (a *AlertsConfig) validate() error {
+	for i := range a.Routes {
+		if err := a.Routes[i].validate(); err != nil {
+			return fmt.Errorf("routes[%d]: %v", i, err)
+		}
+	}
+
+	if e := a.Escalation; e != nil {
+		if e.AfterMinutes <= 0 {
+			e.AfterMinutes = 15
+		}
+		if e.MinSeverity == "" {
+			e.MinSeverity = "critical"
+		}
+		if err := e.Route.validate(); err != nil {
+			return fmt.Errorf("escalation: %v", err)
+		}
+	}
+	return nil
+}
+
+func (r *AlertRoute) validate() error {
+	switch r.MinSeverity {
+	case "":
+		r.MinSeverity = "ok"
+	case "ok", "warning", "critical":
+	default:
+		return fmt.Errorf("unknown min_severity %q", r.MinSeverity)
+	}
+
+	switch r.Type {
+	case "telegram":
+		if r.ChatID == 0 {
+			return fmt.Errorf("telegram route needs chat_id")
+		}
+	case "webhook":
+		if r.URL == "" {
+			return fmt.Errorf("webhook route needs url")
+		}
+	case "smtp":
+		if r.SMTP.Host == "" || r.SMTP.From == "" || len(r.SMTP.To) == 0 {
+			return fmt.Errorf("smtp route needs host, from and to")
+		}
+		if r.SMTP.Port == 0 {
+			r.SMTP.Port = 587
+		}
+	default:
+		return fmt.Errorf("unknown type %q", r.Type)
+	}
+
+	if r.Name == "" {
+		r.Name = r.Type
+	}
+	return nil
+}
+
+// AdminIDs returns AdminID and every user with the admin role
+func (c *Config) AdminIDs() []int64 {
+	ids := []int64{c.AdminID}
+	for _, u := range c.Users {
+		if u.ID != c.AdminID && u.Role == RoleAdmin {
+			ids = append(ids, u.ID)
+		}
+	}
+	return ids
+}
+
+func applyBatteryDefaults(b *BatteryConfig) {
+	if b.WarningPercent == 0 {
+		b.WarningPercent = 20
+	}
+	if b.CriticalPercent == 0 {
+		b.CriticalPercent = 10
+	}
+	if b.ShutdownPercent == 0 {
+		b.ShutdownPercent = 5
+	}
+	if b.ChargedPercent == 0 {
+		b.ChargedPercent = 80
+	}
+	if b.Hysteresis == 0 {
+		b.Hysteresis = 3
+	}
+	if b.TempWarning == 0 {
+		b.TempWarning = 40
+	}
+	if b.TempCritical == 0 {
+		b.TempCritical = 45
+	}
+	if b.TempHysteresis == 0 {
+		b.TempHysteresis = 2
+	}
+	if b.TempCooldown == 0 {
+		b.TempCooldown = 30
+	}
+	if b.TempWarning >= b.TempCritical {
+		log.Fatal("battery thresholds must satisfy temp_warning < temp_critical")
+	}
+	if !(b.ShutdownPercent < b.CriticalPercent && b.CriticalPercent < b.WarningPercent) {
+		log.Fatal("battery thresholds must satisfy shutdown_percent < critical_percent < warning_percent")
+	}
+}
+
+func applyDiskDefaults(d *DiskConfig) {
+	if len(d.Paths) == 0 {
+		d.Paths = []string{"/data", FilesDir()}
+	}
+	for i, path := range d.Paths {
+		d.Paths[i] = expandHome(path)
+	}
+	if d.WarningPercent == 0 {
+		d.WarningPercent = 85
+	}
+	if d.CriticalPercent == 0 {
+		d.CriticalPercent = 95
+	}
+	if d.MinFreeMB == 0 {
+		d.MinFreeMB = 500
+	}
+	if d.PredictHours == 0 {
+		d.PredictHours = 72
+	}
+}
+
+func applyProcessDefaults(processes []ProcessConfig, seen map[string]bool) {
+	for i := range processes {
+		p := &processes[i]
+		if p.Name == "" {
+			log.Fatalf("processes[%d]: name is required", i)
+		}
+		if seen[p.Name] {
+			log.Fatalf("processes: check name %q is already used", p.Name)
+		}
+		seen[p.Name] = true
+
+		if p.Pidfile == "" && p.Pattern == "" && p.Command == "" {
+			log.Fatalf("process %q: one of pidfile, pattern or command is required", p.Name)
+		}
+		if p.AutoRestart && p.Command == "" {
+			log.Fatalf("process %q: auto_restart requires command", p.Name)
+		}
+		if p.MaxRestartsPerHour == 0 {
+			p.MaxRestartsPerHour = 3
+		}
+		if p.BackoffSeconds == 0 {
+			p.BackoffSeconds = 10
+		}
+		if p.IntervalSeconds == 0 {
+			p.IntervalSeconds = 60
+		}
+	}
+}
+
+func applyScheduleDefaults(tasks []TaskConfig) {
+	seen := make(map[string]bool)
+	for i := range tasks {
+		t := &tasks[i]
+		if t.Name == "" {
+			log.Fatalf("schedule[%d]: name is required", i)
+		}
+		// Numeric IDs belong to tasks added from chat
+		if _, err := strconv.Atoi(t.Name); err == nil {
+			log.Fatalf("schedule[%d]: name %q must not be a number", i, t.Name)
+		}
+		if seen[t.Name] {
+			log.Fatalf("schedule: task name %q is already used", t.Name)
+		}
+		seen[t.Name] = true
+
+		if t.Cron == "" || t.Command == "" {
+			log.Fatalf("task %q: cron and command are required", t.Name)
+		}
+		switch t.Report {
+		case "":
+			t.Report = "failures"
+		case "failures", "all":
+		default:
+			log.Fatalf("task %q: unknown report %q (use failures or all)", t.Name, t.Report)
+		}
+		if t.TimeoutMinutes <= 0 {
+			t.TimeoutMinutes = 10
+		}
+	}
+}
+
+// programName keeps program names usable as file names and button data
+var programName = regexp.MustCompile(`^[A-Za-z0-9_-][A-Za-z0-9_.-]{0,31}$`)
+
+func applyProgramDefaults(programs []ProgramConfig) {
+	seen := make(map[string]bool)
+	for i := range programs {
+		p := &programs[i]
+		if !programName.MatchString(p.Name) {
+			log.Fatalf("programs[%d]: invalid name %q (letters, digits, '.', '-' and '_')", i, p.Name)
+		}
+		if seen[p.Name] {
+			log.Fatalf("programs: name %q is already used", p.Name)
+		}
+		seen[p.Name] = true
+
+		if p.Command == "" {
+			log.Fatalf("program %q: command is required", p.Name)
+		}
+		switch p.Restart {
+		case "":
+			p.Restart = "always"
+		case "always", "on-failure", "never":
+		default:
+			log.Fatalf("program %q: unknown restart %q (use always, on-failure or never)", p.Name, p.Restart)
+		}
+		p.Dir = expandHome(p.Dir)
+		p.LogFile = expandHome(p.LogFile)
+		if p.LogMaxKB <= 0 {
+			p.LogMaxKB = 1024
+		}
+		if p.LogBackups <= 0 {
+			p.LogBackups = 3
+		}
+		if p.StopTimeoutSeconds <= 0 {
+			p.StopTimeoutSeconds = 10
+		}
+	}
+}
+
+func applyEndpointDefaults(endpoints []EndpointConfig, seen map[string]bool) {
+	for i := range endpoints {
+		e := &endpoints[i]
+		if e.Name == "" {
+			log.Fatalf("endpoints[%d]: name is required", i)
+		}
+		if seen[e.Name] {
+			log.Fatalf("endpoints: check name %q is already used", e.Name)
+		}
+		seen[e.Name] = true
+
+		if (e.URL == "") == (e.Address == "") {
+			log.Fatalf("endpoint %q: exactly one of url or address is required", e.Name)
+		}
+		if e.MaxLatencyMs == 0 {
+			e.MaxLatencyMs = 2000
+		}
+		if e.TLSExpiryDays == 0 {
+			e.TLSExpiryDays = 14
+		}
+		if e.TimeoutSeconds == 0 {
+			e.TimeoutSeconds = 10
+		}
+		if e.IntervalSeconds == 0 {
+			e.IntervalSeconds = 60
+		}
+	}
+}
+
+// MaintenanceWindow is a recurring period during which watchdog alerts
+// are suppressed. A window whose end is before its start runs past midnight.
+type MaintenanceWindow struct {
+	Days   []string `json:"days"`   // mon..sun, empty means every day
+	Start  string   `json:"start"`  // local time, "02:00"
+	End    string   `json:"end"`    // local time, "04:30"
+	Checks []string `json:"checks"` // empty means all checks
+
+	start, end int // minutes since midnight
+	days       map[time.Weekday]bool
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// parse validates the window and fills its parsed fields
+func (m *MaintenanceWindow) parse() error {
+	var err error
+	if m.start, err = parseClock(m.Start); err != nil {
+		return fmt.Errorf("invalid start: %v", err)
+	}
+	if m.end, err = parseClock(m.End); err != nil {
+		return fmt.Errorf("invalid end: %v", err)
+	}
+	if m.start == m.end {
+		return fmt.Errorf("start and end must differ")
+	}
+
+	if len(m.Days) > 0 {
+		m.days = make(map[time.Weekday]bool)
+		for _, day := range m.Days {
+			weekday, ok := weekdays[strings.ToLower(day)]
+			if !ok {
+				return fmt.Errorf("unknown day %q", day)
+			}
+			m.days[weekday] = true
+		}
+	}
+	return nil
+}
+
+// parseClock converts "HH:MM" to minutes since midnight
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// Active reports whether the window covers the check at the given time
+func (m *MaintenanceWindow) Active(check string, t time.Time) bool {
+	if len(m.Checks) > 0 {
+		found := false
+		for _, name := range m.Checks {
+			found = found || name == check
+		}
+		if !found {
+			return false
+		}
+	}
+
+	onDay := func(d time.Weekday) bool { return m.days == nil || m.days[d] }
+	minute := t.Hour()*60 + t.Minute()
+	if m.start < m.end {
+		return onDay(t.Weekday()) && minute >= m.start && minute < m.end
+	}
+
+	// Past midnight: the days refer to the day the window starts
+	yesterday := (t.Weekday() + 6) % 7
+	return onDay(t.Weekday()) && minute >= m.start || onDay(yesterday) && minute < m.end
+}
+
+// expandHome replaces a leading ~/ with the home directory
+func expandHome(path string) string {
+	if !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, path[2:])
+}
+
+// FilesDir returns ~/asb_files, the root for uploaded files
+func FilesDir() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, "asb_files")
+}
+
 func setupDirectories(storagePath string) {
 	home, _ := os.UserHomeDir()
 
@@ -0,0 +1,146 @@
+package bot
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"android-server-brain/config"
+	"android-server-brain/internal/i18n"
+	"android-server-brain/internal/system"
+	"android-server-brain/internal/transport"
+)
+
+// registerAlertHandlers registers the alert buttons and the mute commands
+func (r *Registry) registerAlertHandlers(watchdog *system.Watchdog) {
+	r.RegisterButton(system.ButtonSnooze, config.RoleOperator, func(c transport.Context) error {
+		return r.mute(c, watchdog, c.Data(), time.Now().Add(system.SnoozeDuration))
+	})
+
+	r.RegisterButton(system.ButtonMute, config.RoleOperator, func(c transport.Context) error {
+		return r.mute(c, watchdog, c.Data(), tomorrowMorning(time.Now()))
+	})
+
+	r.RegisterButton(system.ButtonAck, config.RoleOperator, func(c transport.Context) error {
+		lang := r.lang(c)
+		if !watchdog.Acknowledge(c.Data()) {
+			return c.Respond(i18n.T(lang, "alert.ack_stale"))
+		}
+		c.Respond(i18n.T(lang, "alert.ack_button"))
+		return c.Send(i18n.T(lang, "alert.acked", r.userName(c.SenderID())), transport.ModeMarkdown)
+	})
+
+	r.Register(Command{
+		Name:        "mute",
+		Description: "cmd.mute",
+		Usage:       "/mute [<check>|all <duration>]",
+		Role:        config.RoleOperator,
+		Handler: func(c transport.Context) error {
+			lang := r.lang(c)
+			args := c.Args()
+			if len(args) == 0 {
+				return c.Send(mutesText(lang, watchdog.Mutes()), transport.ModeMarkdown)
+			}
+			if len(args) != 2 {
+				return c.Send(i18n.T(lang, "mute.usage"), transport.ModeMarkdown)
+			}
+
+			until, err := parseMuteUntil(args[1], time.Now())
+			if err != nil {
+				return c.Send(i18n.T(lang, "mute.bad_duration", args[1]), transport.ModeMarkdown)
+			}
+			return r.mute(c, watchdog, args[0], until)
+		},
+	})
+
+	r.Register(Command{
+		Name:        "unmute",
+		Description: "cmd.unmute",
+		Usage:       "/unmute <check>|all",
+		Role:        config.RoleOperator,
+		Handler: func(c transport.Context) error {
+			lang := r.lang(c)
+			args := c.Args()
+			if len(args) != 1 {
+				return c.Send(i18n.T(lang, "unmute.usage"), transport.ModeMarkdown)
+			}
+			if !watchdog.Unmute(args[0]) {
+				return c.Send(i18n.T(lang, "unmute.not_muted", args[0]), transport.ModeMarkdown)
+			}
+			return c.Send(i18n.T(lang, "unmute.done", args[0]), transport.ModeMarkdown)
+		},
+	})
+}
+
+// mute silences a check and confirms it to the user
+func (r *Registry) mute(c transport.Context, watchdog *system.Watchdog, check string, until time.Time) error {
+	lang := r.lang(c)
+	if check != system.MuteAll && !watchdog.HasCheck(check) {
+		return c.Send(i18n.T(lang, "mute.unknown_check", check), transport.ModeMarkdown)
+	}
+
+	watchdog.Mute(check, until)
+	text := i18n.T(lang, "mute.done", check, until.Format(i18n.T(lang, "mute.time_format")))
+	if c.Data() != "" {
+		c.Respond(i18n.T(lang, "mute.done_short"))
+	}
+	return c.Send(text, transport.ModeMarkdown)
+}
+
+// mutesText lists the active mutes
+func mutesText(lang i18n.Lang, mutes map[string]time.Time) string {
+	if len(mutes) == 0 {
+		return i18n.T(lang, "mute.none") + "\n\n" + i18n.T(lang, "mute.usage")
+	}
+
+	names := make([]string, 0, len(mutes))
+	for name := range mutes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString(i18n.T(lang, "mute.list_title"))
+	for _, name := range names {
+		b.WriteString(i18n.T(lang, "mute.list_line", name, mutes[name].Format(i18n.T(lang, "mute.time_format"))))
+	}
+	return b.String()
+}
+
+var daysPrefix = regexp.MustCompile(`^(\d+)d`)
+
+// parseMuteUntil parses "tomorrow" or a duration such as 30m, 2h or 1d12h
+func parseMuteUntil(s string, now time.Time) (time.Time, error) {
+	if strings.EqualFold(s, "tomorrow") {
+		return tomorrowMorning(now), nil
+	}
+
+	var days time.Duration
+	if m := daysPrefix.FindStringSubmatch(s); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		days = time.Duration(n) * 24 * time.Hour
+		s = s[len(m[0]):]
+	}
+
+	var rest time.Duration
+	if s != "" {
+		var err error
+		if rest, err = time.ParseDuration(s); err != nil {
+			return time.Time{}, err
+		}
+	}
+
+	if d := days + rest; d > 0 {
+		return now.Add(d), nil
+	}
+	return time.Time{}, fmt.Errorf("duration must be positive")
+}
+
+// tomorrowMorning returns 09:00 local time on the next day
+func tomorrowMorning(now time.Time) time.Time {
+	y, m, d := now.AddDate(0, 0, 1).Date()
+	return time.Date(y, m, d, 9, 0, 0, 0, now.Location())
+}
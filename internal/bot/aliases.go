@@ -0,0 +1,285 @@
+package bot
+
+import (
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"android-server-brain/config"
+	"android-server-brain/internal/i18n"
+	"android-server-brain/internal/storage"
+	"android-server-brain/internal/transport"
+)
+
+// buttonAliasRun runs a favorite alias from the inline keyboard
+const buttonAliasRun = "alias_run"
+
+var (
+	// aliasName keeps names short enough for inline button data
+	aliasName = regexp.MustCompile(`^[a-z0-9_-]{1,32}$`)
+	// aliasParam matches the $1..$9 and $@ placeholders
+	aliasParam = regexp.MustCompile(`\$([1-9@])`)
+)
+
+// Alias is a saved shell command, optionally with $1..$9 and $@ placeholders
+type Alias struct {
+	Name     string `json:"name"`
+	Command  string `json:"command"`
+	Favorite bool   `json:"favorite"`
+}
+
+// Params returns the number of positional arguments the alias needs and
+// whether it takes any number of extra arguments through $@
+func (a Alias) Params() (n int, variadic bool) {
+	for _, m := range aliasParam.FindAllStringSubmatch(a.Command, -1) {
+		if m[1] == "@" {
+			variadic = true
+			continue
+		}
+		if i, _ := strconv.Atoi(m[1]); i > n {
+			n = i
+		}
+	}
+	return n, variadic
+}
+
+// Expand substitutes the placeholders; the caller checks the argument count
+func (a Alias) Expand(args []string) string {
+	return aliasParam.ReplaceAllStringFunc(a.Command, func(p string) string {
+		if p == "$@" {
+			return strings.Join(args, " ")
+		}
+		i, _ := strconv.Atoi(p[1:])
+		if i > len(args) {
+			return ""
+		}
+		return args[i-1]
+	})
+}
+
+// AliasStore keeps the saved aliases in a JSON file
+type AliasStore struct {
+	path string
+
+	mu      sync.RWMutex
+	aliases map[string]Alias
+}
+
+// NewAliasStore loads aliases from path; an empty path keeps them in memory only
+func NewAliasStore(path string) *AliasStore {
+	s := &AliasStore{path: path, aliases: make(map[string]Alias)}
+	if path != "" {
+		if err := storage.LoadJSON(path, &s.aliases); err != nil {
+			log.Printf("Failed to load aliases: %v", err)
+		}
+	}
+	return s
+}
+
+// Get returns an alias by name
+func (s *AliasStore) Get(name string) (Alias, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	a, ok := s.aliases[name]
+	return a, ok
+}
+
+// List returns all aliases sorted by name
+func (s *AliasStore) List() []Alias {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	list := make([]Alias, 0, len(s.aliases))
+	for _, a := range s.aliases {
+		list = append(list, a)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// Set adds or replaces an alias, keeping the favorite flag of a replaced one
+func (s *AliasStore) Set(name, command string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.aliases[name] = Alias{Name: name, Command: command, Favorite: s.aliases[name].Favorite}
+	return s.save()
+}
+
+// SetFavorite toggles whether an alias is on the favorites keyboard
+func (s *AliasStore) SetFavorite(name string, favorite bool) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a, ok := s.aliases[name]
+	if !ok {
+		return false, nil
+	}
+	a.Favorite = favorite
+	s.aliases[name] = a
+	return true, s.save()
+}
+
+// Remove deletes an alias and reports whether it existed
+func (s *AliasStore) Remove(name string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.aliases[name]; !ok {
+		return false, nil
+	}
+	delete(s.aliases, name)
+	return true, s.save()
+}
+
+// save writes the aliases, the caller holds mu
+func (s *AliasStore) save() error {
+	if s.path == "" {
+		return nil
+	}
+	return storage.SaveJSON(s.path, s.aliases)
+}
+
+// registerAliasHandlers registers /alias, /run and the favorites keyboard
+func (r *Registry) registerAliasHandlers(aliases *AliasStore) {
+	r.RegisterButton(buttonAliasRun, config.RoleAdmin, func(c transport.Context) error {
+		c.Respond(c.Data())
+		return r.runAlias(c, aliases, c.Data(), nil)
+	})
+
+	r.Register(Command{
+		Name:        "alias",
+		Description: "cmd.alias",
+		Usage:       "/alias [list|add <name> \"<command>\"|rm <name>|fav <name>|unfav <name>]",
+		Role:        config.RoleAdmin,
+		Handler: func(c transport.Context) error {
+			lang := r.lang(c)
+			args := c.Args()
+			if len(args) == 0 {
+				return r.sendFavorites(c, aliases)
+			}
+
+			switch {
+			case args[0] == "list":
+				return c.Send(aliasesText(lang, aliases.List()), transport.ModeMarkdown)
+
+			case args[0] == "add" && len(args) >= 3:
+				name := args[1]
+				if !aliasName.MatchString(name) {
+					return c.Send(i18n.T(lang, "alias.bad_name", name), transport.ModeMarkdown)
+				}
+				command := strings.Join(args[2:], " ")
+				if len(command) >= 2 && strings.HasPrefix(command, `"`) && strings.HasSuffix(command, `"`) {
+					command = command[1 : len(command)-1]
+				}
+				if strings.TrimSpace(command) == "" {
+					return c.Send(i18n.T(lang, "alias.usage"), transport.ModeMarkdown)
+				}
+				if err := aliases.Set(name, command); err != nil {
+					return c.Send(i18n.T(lang, "alias.save_failed", err), transport.ModeMarkdown)
+				}
+				return c.Send(i18n.T(lang, "alias.added", name, command), transport.ModeMarkdown)
+
+			case args[0] == "rm" && len(args) == 2:
+				ok, err := aliases.Remove(args[1])
+				if err != nil {
+					return c.Send(i18n.T(lang, "alias.save_failed", err), transport.ModeMarkdown)
+				}
+				if !ok {
+					return c.Send(i18n.T(lang, "alias.unknown", args[1]), transport.ModeMarkdown)
+				}
+				return c.Send(i18n.T(lang, "alias.removed", args[1]), transport.ModeMarkdown)
+
+			case (args[0] == "fav" || args[0] == "unfav") && len(args) == 2:
+				favorite := args[0] == "fav"
+				ok, err := aliases.SetFavorite(args[1], favorite)
+				if err != nil {
+					return c.Send(i18n.T(lang, "alias.save_failed", err), transport.ModeMarkdown)
+				}
+				if !ok {
+					return c.Send(i18n.T(lang, "alias.unknown", args[1]), transport.ModeMarkdown)
+				}
+				if favorite {
+					return c.Send(i18n.T(lang, "alias.favorited", args[1]), transport.ModeMarkdown)
+				}
+				return c.Send(i18n.T(lang, "alias.unfavorited", args[1]), transport.ModeMarkdown)
+
+			default:
+				return c.Send(i18n.T(lang, "alias.usage"), transport.ModeMarkdown)
+			}
+		},
+	})
+
+	r.Register(Command{
+		Name:        "run",
+		Description: "cmd.run",
+		Usage:       "/run <alias> [args...]",
+		Role:        config.RoleAdmin,
+		Handler: func(c transport.Context) error {
+			args := c.Args()
+			if len(args) == 0 {
+				return r.sendFavorites(c, aliases)
+			}
+			return r.runAlias(c, aliases, args[0], args[1:])
+		},
+	})
+}
+
+// runAlias expands an alias with the given arguments and runs it like /exec
+func (r *Registry) runAlias(c transport.Context, aliases *AliasStore, name string, args []string) error {
+	lang := r.lang(c)
+	a, ok := aliases.Get(name)
+	if !ok {
+		return c.Send(i18n.T(lang, "alias.unknown", name), transport.ModeMarkdown)
+	}
+
+	n, variadic := a.Params()
+	if len(args) < n || (len(args) > n && !variadic) {
+		return c.Send(i18n.T(lang, "alias.bad_args", name, n, len(args), a.Command), transport.ModeMarkdown)
+	}
+	return r.runShell(c, a.Expand(args))
+}
+
+// sendFavorites sends the inline keyboard of favorite aliases
+func (r *Registry) sendFavorites(c transport.Context, aliases *AliasStore) error {
+	lang := r.lang(c)
+
+	var kb transport.Keyboard
+	var row []transport.Button
+	for _, a := range aliases.List() {
+		if !a.Favorite {
+			continue
+		}
+		row = append(row, transport.Button{Text: "▶️ " + a.Name, Unique: buttonAliasRun, Data: a.Name})
+		if len(row) == 2 {
+			kb = append(kb, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		kb = append(kb, row)
+	}
+
+	if len(kb) == 0 {
+		return c.Send(i18n.T(lang, "alias.no_favorites"), transport.ModeMarkdown)
+	}
+	return c.Send(i18n.T(lang, "alias.favorites"), transport.ModeMarkdown, kb)
+}
+
+// aliasesText renders the list of saved aliases
+func aliasesText(lang i18n.Lang, aliases []Alias) string {
+	if len(aliases) == 0 {
+		return i18n.T(lang, "alias.empty")
+	}
+
+	var b strings.Builder
+	b.WriteString(i18n.T(lang, "alias.title"))
+	for _, a := range aliases {
+		star := ""
+		if a.Favorite {
+			star = "⭐️ "
+		}
+		b.WriteString(i18n.T(lang, "alias.line", star, a.Name, a.Command))
+	}
+	return b.String()
+}
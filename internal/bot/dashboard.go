@@ -0,0 +1,243 @@
+package bot
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"android-server-brain/config"
+	"android-server-brain/internal/i18n"
+	"android-server-brain/internal/system"
+	"android-server-brain/internal/transport"
+)
+
+// Dashboard buttons; the view button carries the view name as data
+const (
+	buttonDashboard    = "dash"
+	buttonDashboardPin = "dash_pin"
+)
+
+// Dashboard views
+const (
+	viewOverview = "overview"
+	viewBattery  = "battery"
+	viewDisk     = "disk"
+	viewJobs     = "jobs"
+	viewServices = "services"
+	viewReboot   = "reboot"
+)
+
+// dashboardRoles lists the views that need more than the viewer role
+var dashboardRoles = map[string]config.Role{
+	viewJobs:   config.RoleAdmin,
+	viewReboot: config.RoleAdmin,
+}
+
+// dashboard tracks the latest /dashboard message of every chat so pinned
+// ones can be refreshed in place
+type dashboard struct {
+	watchdog  *system.Watchdog
+	scheduler *system.Scheduler
+
+	mu    sync.Mutex
+	chats map[int64]*dashboardMessage
+}
+
+type dashboardMessage struct {
+	ref    transport.MessageRef
+	view   string
+	pinned bool
+}
+
+// registerDashboard registers /dashboard and its buttons
+func (r *Registry) registerDashboard(watchdog *system.Watchdog, scheduler *system.Scheduler) {
+	r.dashboard = &dashboard{
+		watchdog:  watchdog,
+		scheduler: scheduler,
+		chats:     make(map[int64]*dashboardMessage),
+	}
+
+	r.Register(Command{
+		Name:        "dashboard",
+		Description: "cmd.dashboard",
+		Role:        config.RoleViewer,
+		Handler: func(c transport.Context) error {
+			text, kb := r.renderDashboard(r.lang(c), r.role(c), viewOverview, false)
+			ref, err := c.Notifier().Notify(c.ChatID(), text, transport.ModeMarkdown, kb)
+			if err != nil {
+				// Frontends without out-of-band messages get a plain reply
+				return c.Send(text, transport.ModeMarkdown, kb)
+			}
+
+			r.dashboard.mu.Lock()
+			r.dashboard.chats[c.ChatID()] = &dashboardMessage{ref: ref, view: viewOverview}
+			r.dashboard.mu.Unlock()
+			return nil
+		},
+	})
+
+	r.RegisterButton(buttonDashboard, config.RoleViewer, func(c transport.Context) error {
+		view := c.Data()
+		role, ok := dashboardRoles[view]
+		if !ok {
+			role = config.RoleViewer
+		}
+
+		return r.guard(role, func(c transport.Context) error {
+			c.Respond("")
+			pinned := false
+			r.dashboard.mu.Lock()
+			if m, ok := r.dashboard.chats[c.ChatID()]; ok {
+				m.view = view
+				pinned = m.pinned
+			}
+			r.dashboard.mu.Unlock()
+
+			text, kb := r.renderDashboard(r.lang(c), r.role(c), view, pinned)
+			return c.Edit(text, transport.ModeMarkdown, kb)
+		})(c)
+	})
+
+	r.RegisterButton(buttonDashboardPin, config.RoleViewer, func(c transport.Context) error {
+		lang := r.lang(c)
+		pinner, ok := r.frontend.(transport.Pinner)
+		r.dashboard.mu.Lock()
+		m, tracked := r.dashboard.chats[c.ChatID()]
+		r.dashboard.mu.Unlock()
+		if !ok || !tracked {
+			return c.Respond(i18n.T(lang, "dashboard.stale"))
+		}
+
+		pin := c.Data() == "pin"
+		var err error
+		if pin {
+			err = pinner.Pin(m.ref)
+		} else {
+			err = pinner.Unpin(m.ref)
+		}
+		if err != nil {
+			return c.Send(i18n.T(lang, "dashboard.pin_failed", err), transport.ModeMarkdown)
+		}
+
+		r.dashboard.mu.Lock()
+		m.pinned = pin
+		view := m.view
+		r.dashboard.mu.Unlock()
+
+		c.Respond("")
+		text, kb := r.renderDashboard(lang, r.role(c), view, pin)
+		return c.Edit(text, transport.ModeMarkdown, kb)
+	})
+}
+
+// StartDashboard refreshes pinned dashboards until ctx is cancelled.
+// Frontends that can't tell which message is pinned are skipped.
+func (r *Registry) StartDashboard(ctx context.Context) {
+	pinner, ok := r.frontend.(transport.Pinner)
+	if r.dashboard == nil || !ok {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(time.Duration(r.cfg.Dashboard.RefreshSeconds) * time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				r.refreshDashboards(pinner)
+			}
+		}
+	}()
+}
+
+// refreshDashboards edits every dashboard that is still its chat's pinned message
+func (r *Registry) refreshDashboards(pinner transport.Pinner) {
+	r.dashboard.mu.Lock()
+	chats := make(map[int64]dashboardMessage, len(r.dashboard.chats))
+	for id, m := range r.dashboard.chats {
+		chats[id] = *m
+	}
+	r.dashboard.mu.Unlock()
+
+	for chatID, m := range chats {
+		// A pending reboot confirmation must not be replaced
+		if m.view == viewReboot {
+			continue
+		}
+
+		pinnedRef, ok, err := pinner.Pinned(chatID)
+		if err != nil {
+			log.Printf("Failed to get pinned message of %d: %v", chatID, err)
+			continue
+		}
+		pinned := ok && pinnedRef == m.ref
+
+		r.dashboard.mu.Lock()
+		if current, ok := r.dashboard.chats[chatID]; ok && current.ref == m.ref {
+			current.pinned = pinned
+		}
+		r.dashboard.mu.Unlock()
+		if !pinned {
+			continue
+		}
+
+		role, _ := r.cfg.RoleOf(chatID)
+		text, kb := r.renderDashboard(r.langs.Get(chatID, ""), role, m.view, true)
+		if err := r.frontend.EditMessage(m.ref, text, transport.ModeMarkdown, kb); err != nil {
+			log.Printf("Failed to refresh dashboard in %d: %v", chatID, err)
+		}
+	}
+}
+
+// renderDashboard returns the text and keyboard of a dashboard view. Buttons
+// of views the role can't open are left out.
+func (r *Registry) renderDashboard(lang i18n.Lang, role config.Role, view string, pinned bool) (string, transport.Keyboard) {
+	d := r.dashboard
+	if view == viewReboot {
+		return i18n.T(lang, "reboot.confirm"), transport.Keyboard{
+			transport.Row(transport.Button{Text: i18n.T(lang, "reboot.confirm_button"), Unique: "reboot_confirm", Data: "confirm"}),
+			transport.Row(transport.Button{Text: i18n.T(lang, "dashboard.back"), Unique: buttonDashboard, Data: viewOverview}),
+		}
+	}
+
+	var text string
+	switch view {
+	case viewBattery:
+		text = system.GetBatteryInfo(lang)
+	case viewDisk:
+		text = system.GetDiskUsage(lang, config.FilesDir(), "", 10)
+	case viewJobs:
+		text = scheduleText(lang, d.scheduler.Tasks())
+	case viewServices:
+		// The dashboard has its own buttons, render the plain list
+		text, _ = r.servicesView(lang, config.RoleViewer)
+	default:
+		text = system.GetSystemStatus(lang) + "\n\n" + d.watchdog.GetChecksTable(lang)
+	}
+	text += i18n.T(lang, "dashboard.updated", time.Now().Format("15:04:05"))
+
+	var kb transport.Keyboard
+	for _, views := range [][]string{{viewOverview, viewBattery, viewDisk}, {viewJobs, viewServices, viewReboot}} {
+		var row []transport.Button
+		for _, v := range views {
+			if required, ok := dashboardRoles[v]; ok && !role.Allows(required) {
+				continue
+			}
+			row = append(row, transport.Button{Text: i18n.T(lang, "dashboard."+v), Unique: buttonDashboard, Data: v})
+		}
+		kb = append(kb, row)
+	}
+
+	if _, ok := r.frontend.(transport.Pinner); ok {
+		pin := transport.Button{Text: i18n.T(lang, "dashboard.pin"), Unique: buttonDashboardPin, Data: "pin"}
+		if pinned {
+			pin = transport.Button{Text: i18n.T(lang, "dashboard.unpin"), Unique: buttonDashboardPin, Data: "unpin"}
+		}
+		kb = append(kb, transport.Row(pin))
+	}
+	return text, kb
+}
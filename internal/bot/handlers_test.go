@@ -0,0 +1,298 @@
+package bot
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"android-server-brain/config"
+	"android-server-brain/internal/i18n"
+	"android-server-brain/internal/system"
+	"android-server-brain/internal/transport"
+	"android-server-brain/internal/transport/memory"
+)
+
+const (
+	adminID    = 1
+	operatorID = 2
+	viewerID   = 3
+	strangerID = 99
+)
+
+// newTestBot registers all handlers on an in-memory frontend
+func newTestBot(t *testing.T) (*memory.Frontend, *config.Config) {
+	t.Helper()
+	cfg := &config.Config{
+		AdminID:    adminID,
+		StorageDir: t.TempDir(),
+		Users: []config.UserConfig{
+			{ID: operatorID, Name: "op", Role: config.RoleOperator},
+			{ID: viewerID, Name: "view", Role: config.RoleViewer},
+		},
+	}
+	f := memory.New()
+	langs := i18n.NewStore("")
+	watchdog := system.NewWatchdog(f, cfg, langs, time.Minute)
+	scheduler, err := system.NewScheduler(f, cfg, langs, watchdog, system.NewHeartbeat(cfg.Heartbeat, watchdog))
+	if err != nil {
+		t.Fatal(err)
+	}
+	RegisterHandlers(f, cfg, langs, watchdog, scheduler, NewAliasStore(""))
+	return f, cfg
+}
+
+// dispatch sends a command and returns the last message it produced
+func dispatch(t *testing.T, f *memory.Frontend, sender int64, text string) memory.Message {
+	t.Helper()
+	f.Reset()
+	if err := f.Dispatch(sender, text); err != nil {
+		t.Fatalf("%s: %v", text, err)
+	}
+	m, ok := f.Last()
+	if !ok {
+		t.Fatalf("%s: no reply", text)
+	}
+	return m
+}
+
+func TestGuardDeniesLowerRoles(t *testing.T) {
+	f, _ := newTestBot(t)
+
+	m := dispatch(t, f, viewerID, "/exec id")
+	if !strings.Contains(m.Text, "*admin*") {
+		t.Errorf("viewer /exec: got %q, want access denied", m.Text)
+	}
+	m = dispatch(t, f, operatorID, "/alias list")
+	if !strings.Contains(m.Text, "*admin*") {
+		t.Errorf("operator /alias: got %q, want access denied", m.Text)
+	}
+
+	// Unknown users get no answer at all
+	f.Reset()
+	f.Dispatch(strangerID, "/status")
+	if msgs := f.Messages(); len(msgs) != 0 {
+		t.Errorf("stranger got %d messages", len(msgs))
+	}
+}
+
+func TestGuardDeniesButtons(t *testing.T) {
+	f, _ := newTestBot(t)
+	ref := transport.MessageRef{ChatID: viewerID, MessageID: 1}
+
+	f.Reset()
+	if err := f.Press(viewerID, ref, buttonService, "restart|sshd"); err != nil {
+		t.Fatal(err)
+	}
+	m, _ := f.Last()
+	if !strings.Contains(m.Text, "*operator*") {
+		t.Errorf("viewer restart button: got %q, want access denied", m.Text)
+	}
+}
+
+func TestHelpFiltersByRole(t *testing.T) {
+	f, _ := newTestBot(t)
+
+	tests := []struct {
+		sender int64
+		shown  []string
+		hidden []string
+	}{
+		{viewerID, []string{"/status", "/services"}, []string{"/exec", "/svc", "/logs", "/schedule"}},
+		{operatorID, []string{"/status", "/svc", "/logs"}, []string{"/exec", "/alias"}},
+		{adminID, []string{"/status", "/svc", "/exec", "/alias"}, nil},
+	}
+	for _, tt := range tests {
+		text := dispatch(t, f, tt.sender, "/help").Text
+		for _, cmd := range tt.shown {
+			if !strings.Contains(text, cmd+" - ") {
+				t.Errorf("help for %d: %s missing", tt.sender, cmd)
+			}
+		}
+		for _, cmd := range tt.hidden {
+			if strings.Contains(text, cmd+" - ") {
+				t.Errorf("help for %d: %s shown", tt.sender, cmd)
+			}
+		}
+	}
+
+	// Details of a command above the role are not revealed
+	if m := dispatch(t, f, viewerID, "/help exec"); !strings.Contains(m.Text, "Unknown command") {
+		t.Errorf("viewer /help exec: got %q", m.Text)
+	}
+	if m := dispatch(t, f, adminID, "/help exec"); !strings.Contains(m.Text, "/exec <command>") {
+		t.Errorf("admin /help exec: got %q", m.Text)
+	}
+}
+
+func TestHelpFollowsClientLanguage(t *testing.T) {
+	f, _ := newTestBot(t)
+
+	f.Reset()
+	if err := f.DispatchLang(viewerID, "ru-RU", "/help"); err != nil {
+		t.Fatal(err)
+	}
+	m, _ := f.Last()
+	if !strings.Contains(m.Text, i18n.T(i18n.Russian, "help.title")) {
+		t.Errorf("got %q, want the Russian help", m.Text)
+	}
+
+	// Updates without a language code, like button presses, keep it
+	if m := dispatch(t, f, viewerID, "/help"); !strings.Contains(m.Text, i18n.T(i18n.Russian, "help.title")) {
+		t.Errorf("got %q, want the remembered Russian", m.Text)
+	}
+}
+
+func TestExecOutput(t *testing.T) {
+	f, _ := newTestBot(t)
+
+	m := dispatch(t, f, adminID, "/exec echo hello from exec")
+	if !strings.Contains(m.Text, "```\nhello from exec\n") {
+		t.Errorf("got %q, want the command output", m.Text)
+	}
+	if msgs := f.Messages(); len(msgs) != 2 || !strings.Contains(msgs[0].Text, "echo hello from exec") {
+		t.Errorf("want a running notice before the output, got %+v", msgs)
+	}
+
+	m = dispatch(t, f, adminID, "/exec true")
+	if !strings.Contains(m.Text, i18n.T(i18n.English, "exec.no_output")) {
+		t.Errorf("got %q, want the no output notice", m.Text)
+	}
+	m = dispatch(t, f, adminID, "/exec")
+	if !strings.Contains(m.Text, "Usage") {
+		t.Errorf("got %q, want usage", m.Text)
+	}
+}
+
+func TestAliasRunsWithArguments(t *testing.T) {
+	f, _ := newTestBot(t)
+
+	dispatch(t, f, adminID, `/alias add greet "echo hi $1 $@"`)
+	if m := dispatch(t, f, adminID, "/run greet bob"); !strings.Contains(m.Text, "hi bob bob") {
+		t.Errorf("got %q", m.Text)
+	}
+	if m := dispatch(t, f, adminID, "/run greet"); !strings.Contains(m.Text, "`greet`") || strings.Contains(m.Text, "Output") {
+		t.Errorf("missing argument: got %q", m.Text)
+	}
+	if m := dispatch(t, f, adminID, "/run nope"); !strings.Contains(m.Text, "nope") {
+		t.Errorf("unknown alias: got %q", m.Text)
+	}
+}
+
+func TestLogsTail(t *testing.T) {
+	f, cfg := newTestBot(t)
+	path := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(path, []byte("one\ntwo\nthree\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg.ServiceLogs = map[string]string{"app": path}
+
+	m := dispatch(t, f, operatorID, "/logs app 2")
+	if !strings.Contains(m.Text, "```\ntwo\nthree\n```") {
+		t.Errorf("got %q, want the last two lines", m.Text)
+	}
+	for _, bad := range []string{"/logs", "/logs app 0", "/logs app many"} {
+		if m := dispatch(t, f, operatorID, bad); !strings.Contains(m.Text, "Usage") {
+			t.Errorf("%s: got %q, want usage", bad, m.Text)
+		}
+	}
+	if m := dispatch(t, f, viewerID, "/logs app"); !strings.Contains(m.Text, "*operator*") {
+		t.Errorf("viewer /logs: got %q, want access denied", m.Text)
+	}
+}
+
+func TestLogsFollowStops(t *testing.T) {
+	f, cfg := newTestBot(t)
+	path := filepath.Join(t.TempDir(), "app.log")
+	os.WriteFile(path, []byte("first\n"), 0644)
+	cfg.ServiceLogs = map[string]string{"app": path}
+
+	m := dispatch(t, f, operatorID, "/logs app follow")
+	if len(m.Options.Keyboard) == 0 || m.Options.Keyboard[0][0].Unique != buttonLogsStop {
+		t.Fatalf("want a stop button, got %+v", m.Options.Keyboard)
+	}
+	if err := f.Press(operatorID, m.Ref, buttonLogsStop, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	want := i18n.T(i18n.English, "logs.follow_ended", "app", "first")
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		msgs := f.Messages()
+		if msgs[0].Text == want {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("got %q, want %q", msgs[0].Text, want)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestDashboardHidesAdminViews(t *testing.T) {
+	f, _ := newTestBot(t)
+
+	has := func(m memory.Message, view string) bool {
+		for _, row := range m.Options.Keyboard {
+			for _, b := range row {
+				if b.Unique == buttonDashboard && b.Data == view {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	if m := dispatch(t, f, viewerID, "/dashboard"); has(m, viewJobs) || has(m, viewReboot) || !has(m, viewBattery) {
+		t.Errorf("viewer dashboard keyboard: %+v", m.Options.Keyboard)
+	}
+	if m := dispatch(t, f, adminID, "/dashboard"); !has(m, viewJobs) || !has(m, viewReboot) {
+		t.Errorf("admin dashboard keyboard: %+v", m.Options.Keyboard)
+	}
+}
+
+func TestServiceResultText(t *testing.T) {
+	status := &system.ServiceStatus{Name: "web", State: system.ServiceUp, PID: 42, Enabled: true}
+	tests := []struct {
+		action string
+		err    error
+		want   string
+		args   []interface{}
+	}{
+		{system.ActionStart, nil, "service.start_ok", []interface{}{"web"}},
+		{system.ActionStart, system.ErrAlreadyRunning, "service.running", []interface{}{"web"}},
+		{system.ActionStop, system.ErrNotRunning, "service.not_running", []interface{}{"web"}},
+		{system.ActionRestart, errors.New("boom"), "service.restart_failed", []interface{}{"web", "boom"}},
+	}
+	for _, tt := range tests {
+		for _, lang := range []i18n.Lang{i18n.English, i18n.Russian} {
+			result := system.ServiceResult{Service: "web", Action: tt.action, Status: status}
+			text := serviceResultText(lang, result, tt.err)
+			if want := i18n.T(lang, tt.want, tt.args...); !strings.HasPrefix(text, want) {
+				t.Errorf("%s %v in %s: got %q, want %q", tt.action, tt.err, lang, text, want)
+			}
+			if !strings.Contains(text, "pid 42") {
+				t.Errorf("%s %v: status missing from %q", tt.action, tt.err, text)
+			}
+		}
+	}
+}
+
+func TestMuteTimesFollowLanguage(t *testing.T) {
+	f, _ := newTestBot(t)
+	until := time.Now().Add(2 * time.Hour)
+
+	f.Reset()
+	if err := f.DispatchLang(operatorID, "ru", "/mute battery 2h"); err != nil {
+		t.Fatal(err)
+	}
+	m, _ := f.Last()
+	if want := until.Format("02.01"); !strings.Contains(m.Text, want) {
+		t.Errorf("got %q, want the date as %s", m.Text, want)
+	}
+	if m := dispatch(t, f, operatorID, "/mute"); !strings.Contains(m.Text, until.Format("02.01")) || strings.Contains(m.Text, until.Format("Jan")) {
+		t.Errorf("mute list: got %q", m.Text)
+	}
+}
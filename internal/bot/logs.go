@@ -0,0 +1,215 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"android-server-brain/config"
+	"android-server-brain/internal/i18n"
+	"android-server-brain/internal/system"
+	"android-server-brain/internal/transport"
+)
+
+// buttonLogsStop ends following a log before the time is up
+const buttonLogsStop = "logs_stop"
+
+const (
+	// serviceLogLines is how much of a log /logs and the logs button show
+	serviceLogLines = 30
+	maxLogLines     = 500
+	// maxLogText keeps a log within one Telegram message
+	maxLogText = 3500
+
+	followLines    = 20
+	followDuration = 5 * time.Minute
+	// followInterval stays well above Telegram's edit rate limit
+	followInterval = 3 * time.Second
+)
+
+// logFollows tracks the log being followed in each chat, one at a time
+type logFollows struct {
+	mu    sync.Mutex
+	chats map[int64]*logFollow
+}
+
+type logFollow struct {
+	cancel context.CancelFunc
+}
+
+// registerLogHandlers registers /logs and its stop button
+func (r *Registry) registerLogHandlers() {
+	r.logs = &logFollows{chats: make(map[int64]*logFollow)}
+
+	r.Register(Command{
+		Name:        "logs",
+		Description: "cmd.logs",
+		Usage:       "/logs <service> [lines|follow]",
+		Role:        config.RoleOperator,
+		Handler: func(c transport.Context) error {
+			lang := r.lang(c)
+			args := c.Args()
+			if len(args) == 0 || len(args) > 2 {
+				return c.Send(i18n.T(lang, "logs.usage"), transport.ModeMarkdown)
+			}
+
+			name, lines := args[0], serviceLogLines
+			if len(args) == 2 {
+				if args[1] == "follow" {
+					return r.followLog(c, name)
+				}
+				n, err := strconv.Atoi(args[1])
+				if err != nil || n < 1 || n > maxLogLines {
+					return c.Send(i18n.T(lang, "logs.usage"), transport.ModeMarkdown)
+				}
+				lines = n
+			}
+			return c.Send(r.serviceLogText(lang, name, lines), transport.ModeMarkdown)
+		},
+	})
+
+	r.RegisterButton(buttonLogsStop, config.RoleOperator, func(c transport.Context) error {
+		r.logs.mu.Lock()
+		if f, ok := r.logs.chats[c.ChatID()]; ok {
+			f.cancel()
+		}
+		r.logs.mu.Unlock()
+		return c.Respond("")
+	})
+}
+
+// serviceLogText renders the tail of a service log
+func (r *Registry) serviceLogText(lang i18n.Lang, name string, lines int) string {
+	text, err := system.ServiceLog(r.cfg, name, lines)
+	if err != nil {
+		return i18n.T(lang, "services.log_failed", name, err)
+	}
+	if strings.TrimSpace(text) == "" {
+		return i18n.T(lang, "services.no_logs", name)
+	}
+	return i18n.T(lang, "services.logs", name, lines, tailText(text, maxLogText))
+}
+
+// followLog sends the end of a log and keeps editing the message with new
+// lines until followDuration passes or the stop button is pressed
+func (r *Registry) followLog(c transport.Context, name string) error {
+	lang := r.lang(c)
+	path, err := system.ServiceLogFile(r.cfg, name)
+	if err != nil {
+		return c.Send(i18n.T(lang, "services.log_failed", name, err), transport.ModeMarkdown)
+	}
+
+	// A log that doesn't exist yet is followed from its first line
+	text, err := system.ServiceLog(r.cfg, name, followLines)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return c.Send(i18n.T(lang, "services.log_failed", name, err), transport.ModeMarkdown)
+	}
+	if text != "" {
+		text += "\n"
+	}
+	var offset int64
+	if info, err := os.Stat(path); err == nil {
+		offset = info.Size()
+	}
+
+	kb := transport.Keyboard{transport.Row(transport.Button{Text: i18n.T(lang, "logs.stop"), Unique: buttonLogsStop})}
+	ref, err := c.Notifier().Notify(c.ChatID(), followText(lang, name, text, true), transport.ModeMarkdown, kb)
+	if err != nil {
+		return c.Send(i18n.T(lang, "logs.follow_unsupported"), transport.ModeMarkdown)
+	}
+
+	// Following again in the same chat ends the previous follow
+	ctx, cancel := context.WithTimeout(context.Background(), followDuration)
+	follow := &logFollow{cancel: cancel}
+	r.logs.mu.Lock()
+	if previous, ok := r.logs.chats[c.ChatID()]; ok {
+		previous.cancel()
+	}
+	r.logs.chats[c.ChatID()] = follow
+	r.logs.mu.Unlock()
+
+	go func() {
+		defer func() {
+			cancel()
+			r.logs.mu.Lock()
+			if r.logs.chats[ref.ChatID] == follow {
+				delete(r.logs.chats, ref.ChatID)
+			}
+			r.logs.mu.Unlock()
+		}()
+
+		ticker := time.NewTicker(followInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				if err := r.frontend.EditMessage(ref, followText(lang, name, text, false), transport.ModeMarkdown); err != nil {
+					log.Printf("Failed to end following %s: %v", name, err)
+				}
+				return
+			case <-ticker.C:
+			}
+
+			data, next, err := system.ReadLogFrom(path, offset)
+			if err != nil {
+				if !errors.Is(err, fs.ErrNotExist) {
+					log.Printf("Failed to follow %s: %v", name, err)
+				}
+				continue
+			}
+			offset = next
+			if data == "" {
+				continue
+			}
+
+			text = lastLines(text+data, followLines)
+			if err := r.frontend.EditMessage(ref, followText(lang, name, text, true), transport.ModeMarkdown, kb); err != nil {
+				log.Printf("Failed to update %s log: %v", name, err)
+			}
+		}
+	}()
+	return nil
+}
+
+// followText renders a followed log, with the stop hint while it's live
+func followText(lang i18n.Lang, name, text string, live bool) string {
+	text = tailText(strings.TrimRight(text, "\n"), maxLogText)
+	if strings.TrimSpace(text) == "" {
+		text = "…"
+	}
+	if live {
+		return i18n.T(lang, "logs.following", name, int(followDuration.Minutes()), text)
+	}
+	return i18n.T(lang, "logs.follow_ended", name, text)
+}
+
+// lastLines keeps the last n lines of text
+func lastLines(text string, n int) string {
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "")
+}
+
+// tailText cuts text to its last max bytes, starting at a line boundary
+func tailText(text string, max int) string {
+	if len(text) <= max {
+		return text
+	}
+	text = text[len(text)-max:]
+	if i := strings.IndexByte(text, '\n'); i >= 0 {
+		text = text[i+1:]
+	}
+	return "…\n" + strings.ToValidUTF8(text, "")
+}
@@ -0,0 +1,193 @@
+package bot
+
+import (
+	"android-server-brain/config"
+	"android-server-brain/internal/i18n"
+	"android-server-brain/internal/transport"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+)
+
+// Command describes a bot command together with its handler
+type Command struct {
+	Name        string // without the leading slash
+	Description string // message key of the one-line description
+	Usage       string // e.g. "/exec <command>"
+	Role        config.Role
+	Hidden      bool // registered but not listed in /help and the menu
+	Handler     transport.HandlerFunc
+}
+
+// Registry registers commands on a frontend and enforces their required role
+type Registry struct {
+	frontend transport.Frontend
+	cfg      *config.Config
+	langs    *i18n.Store
+	commands []*Command
+	byName   map[string]*Command
+
+	dashboard *dashboard
+	logs      *logFollows
+}
+
+// NewRegistry creates a registry for the given frontend
+func NewRegistry(f transport.Frontend, cfg *config.Config, langs *i18n.Store) *Registry {
+	return &Registry{
+		frontend: f,
+		cfg:      cfg,
+		langs:    langs,
+		byName:   make(map[string]*Command),
+	}
+}
+
+// Register adds a command and its handler to the frontend
+func (r *Registry) Register(cmd Command) {
+	c := cmd
+	r.commands = append(r.commands, &c)
+	r.byName[c.Name] = &c
+	r.frontend.Handle("/"+c.Name, r.guard(c.Role, c.Handler))
+}
+
+// RegisterButton adds an inline button handler that requires the given role
+func (r *Registry) RegisterButton(unique string, role config.Role, h transport.HandlerFunc) {
+	r.frontend.HandleButton(unique, r.guard(role, h))
+}
+
+// RegisterDocument adds the handler for incoming files
+func (r *Registry) RegisterDocument(role config.Role, h transport.HandlerFunc) {
+	r.frontend.HandleDocument(r.guard(role, h))
+}
+
+// Lookup returns a command by name, with or without the leading slash
+func (r *Registry) Lookup(name string) (*Command, bool) {
+	c, ok := r.byName[strings.TrimPrefix(name, "/")]
+	return c, ok
+}
+
+// Visible returns the commands a role may use, in registration order
+func (r *Registry) Visible(role config.Role) []*Command {
+	var out []*Command
+	for _, c := range r.commands {
+		if !c.Hidden && role.Allows(c.Role) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// PublishMenu sets the client command menu for every configured user,
+// filtered by their role. Frontends without menu support are skipped.
+func (r *Registry) PublishMenu() {
+	for _, id := range r.cfg.UserIDs() {
+		r.publishMenuFor(id)
+	}
+}
+
+// publishMenuFor sets the command menu of a single user in their language
+func (r *Registry) publishMenuFor(userID int64) {
+	menu, ok := r.frontend.(transport.CommandMenu)
+	if !ok {
+		return
+	}
+
+	role, _ := r.cfg.RoleOf(userID)
+	lang := r.langs.Get(userID, "")
+
+	var cmds []transport.CommandInfo
+	for _, c := range r.Visible(role) {
+		cmds = append(cmds, transport.CommandInfo{Name: c.Name, Description: i18n.T(lang, c.Description)})
+	}
+
+	if err := menu.SetCommands(userID, cmds); err != nil {
+		log.Printf("Failed to set command menu for %d: %v", userID, err)
+	}
+}
+
+// lang returns the language to answer the sender of an update in. The
+// client language is remembered for alerts, digests and the command menu.
+func (r *Registry) lang(c transport.Context) i18n.Lang {
+	if r.langs.Remember(c.SenderID(), c.LanguageCode()) {
+		r.publishMenuFor(c.SenderID())
+	}
+	return r.langs.Get(c.SenderID(), c.LanguageCode())
+}
+
+// role returns the role of the sender of an update
+func (r *Registry) role(c transport.Context) config.Role {
+	role, _ := r.cfg.RoleOf(c.SenderID())
+	return role
+}
+
+// userName returns the configured name of a user, or their ID
+func (r *Registry) userName(id int64) string {
+	for _, u := range r.cfg.Users {
+		if u.ID == id && u.Name != "" {
+			return u.Name
+		}
+	}
+	return strconv.FormatInt(id, 10)
+}
+
+// guard rejects users whose role is below the required one
+func (r *Registry) guard(required config.Role, h transport.HandlerFunc) transport.HandlerFunc {
+	return func(c transport.Context) error {
+		role, ok := r.cfg.RoleOf(c.SenderID())
+		if !ok {
+			return nil // Ignore unknown users
+		}
+		if !role.Allows(required) {
+			lang := r.lang(c)
+			if err := c.Respond(i18n.T(lang, "access.denied_short")); err != nil {
+				return err
+			}
+			return c.Send(i18n.T(lang, "access.denied", required), transport.ModeMarkdown)
+		}
+		return h(c)
+	}
+}
+
+// helpText renders the list of commands available to a role
+func (r *Registry) helpText(lang i18n.Lang, role config.Role) string {
+	var b strings.Builder
+	b.WriteString(i18n.T(lang, "help.title"))
+	for _, c := range r.Visible(role) {
+		fmt.Fprintf(&b, "/%s - %s\n", c.Name, i18n.T(lang, c.Description))
+	}
+	b.WriteString(i18n.T(lang, "help.footer"))
+	return b.String()
+}
+
+// commandHelp renders the detailed help of a single command
+func (r *Registry) commandHelp(lang i18n.Lang, c *Command) string {
+	usage := c.Usage
+	if usage == "" {
+		usage = "/" + c.Name
+	}
+	return i18n.T(lang, "help.command", c.Name, i18n.T(lang, c.Description), usage, c.Role)
+}
+
+// registerHelp adds /help, generated from the registered commands
+func (r *Registry) registerHelp() {
+	r.Register(Command{
+		Name:        "help",
+		Description: "cmd.help",
+		Usage:       "/help [command]",
+		Role:        config.RoleViewer,
+		Handler: func(c transport.Context) error {
+			lang := r.lang(c)
+			role, _ := r.cfg.RoleOf(c.SenderID())
+			args := c.Args()
+			if len(args) == 0 {
+				return c.Send(r.helpText(lang, role), transport.ModeMarkdown)
+			}
+
+			cmd, ok := r.Lookup(args[0])
+			if !ok || cmd.Hidden || !role.Allows(cmd.Role) {
+				return c.Send(i18n.T(lang, "help.unknown", args[0]), transport.ModeMarkdown)
+			}
+			return c.Send(r.commandHelp(lang, cmd), transport.ModeMarkdown)
+		},
+	})
+}
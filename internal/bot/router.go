@@ -2,197 +2,333 @@ package bot
 
 import (
 	"android-server-brain/config"
+	"android-server-brain/internal/i18n"
 	"android-server-brain/internal/storage"
 	"android-server-brain/internal/system"
-	"fmt"
+	"android-server-brain/internal/transport"
+	"context"
+	"errors"
+	"log"
 	"strings"
-
-	tele "gopkg.in/telebot.v3"
+	"time"
 )
 
-func RegisterHandlers(b *tele.Bot, cfg *config.Config, watchdog *system.Watchdog) {
+// RegisterHandlers registers all commands on the frontend and returns the registry
+func RegisterHandlers(b transport.Frontend, cfg *config.Config, langs *i18n.Store, watchdog *system.Watchdog, scheduler *system.Scheduler, aliases *AliasStore) *Registry {
+	r := NewRegistry(b, cfg, langs)
+	r.registerHelp()
+
 	// Standard command handler
-	b.Handle("/start", func(c tele.Context) error {
-		return c.Send("Welcome to Android Server Brain. Use /status to check system health.")
+	r.Register(Command{
+		Name:        "start",
+		Description: "cmd.start",
+		Role:        config.RoleViewer,
+		Handler: func(c transport.Context) error {
+			return c.Send(i18n.T(r.lang(c), "start.welcome"))
+		},
 	})
 
 	// System monitoring handler
-	b.Handle("/status", func(c tele.Context) error {
-		status := system.GetSystemStatus()
-		return c.Send(status, tele.ModeMarkdown)
+	r.Register(Command{
+		Name:        "status",
+		Description: "cmd.status",
+		Role:        config.RoleViewer,
+		Handler: func(c transport.Context) error {
+			status := system.GetSystemStatus(r.lang(c))
+			return c.Send(status, transport.ModeMarkdown)
+		},
 	})
 
+	// Single message overview with buttons
+	r.registerDashboard(watchdog, scheduler)
+
 	// Handle incoming documents (files)
-	b.Handle(tele.OnDocument, func(c tele.Context) error {
-		doc := c.Message().Document
+	r.RegisterDocument(config.RoleAdmin, func(c transport.Context) error {
+		lang := r.lang(c)
+		doc := c.Document()
 
-		c.Send(fmt.Sprintf("📥 Receiving file: %s...", doc.FileName))
-		filePath, err := storage.SaveTelegramFile(b, doc, cfg.StorageDir)
+		c.Send(i18n.T(lang, "file.receiving", doc.FileName))
+		filePath, err := storage.SaveFile(b, doc, cfg.StorageDir)
 		if err != nil {
-			return c.Send(fmt.Sprintf("❌ Error saving file: %v", err))
+			return c.Send(i18n.T(lang, "file.save_failed", err))
 		}
 
-		return c.Send(fmt.Sprintf("✅ File saved and made executable:\n`%s` \n\nYou can run it from `~/asb_files/%s`\n\nLocation: /storage/emulated/0/Download/asb_files/", filePath, doc.FileName), tele.ModeMarkdown)
+		return c.Send(i18n.T(lang, "file.saved", filePath, doc.FileName), transport.ModeMarkdown)
 	})
 
 	// Battery status handler
-	b.Handle("/battery", func(c tele.Context) error {
-		status := system.GetBatteryInfo()
-		return c.Send(status, tele.ModeMarkdown)
+	r.Register(Command{
+		Name:        "battery",
+		Description: "cmd.battery",
+		Role:        config.RoleViewer,
+		Handler: func(c transport.Context) error {
+			status := system.GetBatteryInfo(r.lang(c))
+			return c.Send(status, transport.ModeMarkdown)
+		},
 	})
 
-	// Watchdog status handler
-	b.Handle("/watchdog", func(c tele.Context) error {
-		status := watchdog.GetStatus()
-		return c.Send(status, tele.ModeMarkdown)
+	// Watchdog status and control
+	r.Register(Command{
+		Name:        "watchdog",
+		Description: "cmd.watchdog",
+		Usage:       "/watchdog [pause|resume|interval <duration>|run-now [check]]",
+		Role:        config.RoleViewer,
+		Handler:     r.watchdogHandler(watchdog),
 	})
 
-	// Command execution handler
-	b.Handle("/exec", func(c tele.Context) error {
-		// Extract command from message (remove "/exec " prefix)
-		args := c.Args()
-		if len(args) == 0 {
-			return c.Send("Usage: `/exec <command>`", tele.ModeMarkdown)
-		}
+	// Digest preview
+	r.Register(Command{
+		Name:        "digest",
+		Description: "cmd.digest",
+		Usage:       "/digest [daily|weekly]",
+		Role:        config.RoleViewer,
+		Handler: func(c transport.Context) error {
+			weekly := len(c.Args()) > 0 && c.Args()[0] == "weekly"
+			report := system.NewDigest(b, cfg, langs, watchdog).Report(r.lang(c), weekly, time.Now())
+			return c.Send(report, transport.ModeMarkdown)
+		},
+	})
 
-		fullCommand := strings.Join(args, " ")
-		c.Send(fmt.Sprintf("⏳ Executing: `%s`...", fullCommand), tele.ModeMarkdown)
+	// Compact table of all watchdog checks
+	r.Register(Command{
+		Name:        "checks",
+		Description: "cmd.checks",
+		Role:        config.RoleViewer,
+		Handler: func(c transport.Context) error {
+			return c.Send(watchdog.GetChecksTable(r.lang(c)), transport.ModeMarkdown)
+		},
+	})
 
-		// Run the command
-		output, err := system.ExecuteCommand(fullCommand)
+	// Disk usage of the storage root
+	r.Register(Command{
+		Name:        "du",
+		Description: "cmd.du",
+		Usage:       "/du [path]",
+		Role:        config.RoleViewer,
+		Handler: func(c transport.Context) error {
+			report := system.GetDiskUsage(r.lang(c), config.FilesDir(), strings.Join(c.Args(), " "), 15)
+			return c.Send(report, transport.ModeMarkdown)
+		},
+	})
 
-		// If output is empty, provide a fallback message
-		if strings.TrimSpace(output) == "" {
-			if err != nil {
-				output = "Error: " + err.Error()
-			} else {
-				output = "Command executed successfully (no output)."
+	// Command execution handler
+	r.Register(Command{
+		Name:        "exec",
+		Description: "cmd.exec",
+		Usage:       "/exec <command>",
+		Role:        config.RoleAdmin,
+		Handler: func(c transport.Context) error {
+			lang := r.lang(c)
+
+			// Extract command from message (remove "/exec " prefix)
+			args := c.Args()
+			if len(args) == 0 {
+				return c.Send(i18n.T(lang, "exec.usage"), transport.ModeMarkdown)
 			}
-		}
 
-		// Wrap output in code blocks for readability
-		return c.Send(fmt.Sprintf("📝 *Output:*\n```\n%s\n```", output), tele.ModeMarkdown)
+			watchdog.RecordExec()
+			return r.runShell(c, strings.Join(args, " "))
+		},
 	})
 
-	// Create inline keyboard markup
-	markup := &tele.ReplyMarkup{}
-
-	// Define inline buttons for reboot confirmation using markup helpers
-	rebootConfirmBtn := markup.Data("✅ YES - Reboot System", "reboot_confirm", "confirm")
-	rebootCancelBtn := markup.Data("❌ NO - Cancel", "reboot_cancel", "cancel")
-
-	// Register button callback handlers
-	b.Handle(&rebootConfirmBtn, func(c tele.Context) error {
-		result, err := system.RebootSystem()
+	// Register button callback handlers for reboot confirmation
+	r.RegisterButton("reboot_confirm", config.RoleAdmin, func(c transport.Context) error {
+		result, err := system.RebootSystem(r.lang(c))
 		if err != nil {
-			return c.Send(result, tele.ModeMarkdown)
+			return c.Send(result, transport.ModeMarkdown)
 		}
-		return c.Send(result, tele.ModeMarkdown)
+		return c.Send(result, transport.ModeMarkdown)
 	})
 
-	b.Handle(&rebootCancelBtn, func(c tele.Context) error {
-		return c.Send("❌ Reboot cancelled.", tele.ModeMarkdown)
+	r.RegisterButton("reboot_cancel", config.RoleAdmin, func(c transport.Context) error {
+		return c.Send(i18n.T(r.lang(c), "reboot.cancelled"), transport.ModeMarkdown)
 	})
 
 	// Reboot system handler with inline buttons
-	b.Handle("/reboot", func(c tele.Context) error {
-		markup.Inline(
-			markup.Row(rebootConfirmBtn),
-			markup.Row(rebootCancelBtn),
-		)
+	r.Register(Command{
+		Name:        "reboot",
+		Description: "cmd.reboot",
+		Role:        config.RoleAdmin,
+		Handler: func(c transport.Context) error {
+			lang := r.lang(c)
+			markup := transport.Keyboard{
+				transport.Row(transport.Button{Text: i18n.T(lang, "reboot.confirm_button"), Unique: "reboot_confirm", Data: "confirm"}),
+				transport.Row(transport.Button{Text: i18n.T(lang, "reboot.cancel_button"), Unique: "reboot_cancel", Data: "cancel"}),
+			}
 
-		return c.Send("⚠️ *System Reboot Confirmation*\n\nAre you sure you want to reboot the system? This will disconnect all active sessions.", tele.ModeMarkdown, markup)
+			return c.Send(i18n.T(lang, "reboot.confirm"), transport.ModeMarkdown, markup)
+		},
 	})
 
 	// Restart service handler
-	b.Handle("/restart", func(c tele.Context) error {
-		args := c.Args()
-		if len(args) == 0 {
-			return c.Send(system.ListServices(), tele.ModeMarkdown)
-		}
-
-		serviceName := args[0]
-		c.Send(fmt.Sprintf("⏳ Restarting service: `%s`...", serviceName), tele.ModeMarkdown)
+	r.Register(Command{
+		Name:        "restart",
+		Description: "cmd.restart",
+		Usage:       "/restart [service]",
+		Role:        config.RoleOperator,
+		Handler: func(c transport.Context) error {
+			lang := r.lang(c)
+			args := c.Args()
+			if len(args) == 0 {
+				text, kb := r.servicesView(lang, r.role(c))
+				return c.Send(text, transport.ModeMarkdown, kb)
+			}
 
-		result, err := system.RestartService(serviceName)
-		if err != nil {
-			return c.Send(result, tele.ModeMarkdown)
-		}
+			serviceName := args[0]
+			c.Send(i18n.T(lang, "restart.running", serviceName), transport.ModeMarkdown)
 
-		return c.Send(result, tele.ModeMarkdown)
+			result, err := system.RestartService(serviceName)
+			return c.Send(serviceResultText(lang, result, err), transport.ModeMarkdown)
+		},
 	})
 
+	r.registerServiceHandlers()
+	r.registerLogHandlers()
+
 	// Update system handler
-	b.Handle("/update", func(c tele.Context) error {
-		args := c.Args()
+	r.Register(Command{
+		Name:        "update",
+		Description: "cmd.update",
+		Usage:       "/update [now]",
+		Role:        config.RoleAdmin,
+		Handler: func(c transport.Context) error {
+			lang := r.lang(c)
+			args := c.Args()
+
+			// If no arguments, check for updates
+			if len(args) == 0 {
+				c.Send(i18n.T(lang, "update.checking"), transport.ModeMarkdown)
+
+				result, err := system.CheckForUpdates(lang)
+				if err != nil {
+					return c.Send(i18n.T(lang, "update.check_error", err), transport.ModeMarkdown)
+				}
+
+				if !result.Success {
+					return c.Send(result.Message, transport.ModeMarkdown)
+				}
+
+				// If updates are available, show update options
+				if result.NewVersion != "" {
+					message := i18n.T(lang, "update.offer",
+						result.Message,
+						strings.TrimSpace(result.OldVersion),
+						strings.TrimSpace(result.NewVersion),
+					)
+					return c.Send(message, transport.ModeMarkdown)
+				}
+
+				return c.Send(result.Message, transport.ModeMarkdown)
+			}
 
-		// If no arguments, check for updates
-		if len(args) == 0 {
-			c.Send("🔍 Checking for updates...", tele.ModeMarkdown)
+			// If argument is "now", perform update
+			if args[0] == "now" {
+				c.Send(i18n.T(lang, "update.starting"), transport.ModeMarkdown)
 
-			result, err := system.CheckForUpdates()
-			if err != nil {
-				return c.Send(fmt.Sprintf("❌ Error checking for updates: %v", err), tele.ModeMarkdown)
-			}
+				// Perform update
+				result, err := system.PerformUpdate(lang)
+				if err != nil {
+					return c.Send(i18n.T(lang, "update.failed", err), transport.ModeMarkdown)
+				}
 
-			if !result.Success {
-				return c.Send(result.Message, tele.ModeMarkdown)
-			}
+				if !result.Success {
+					return c.Send(result.Message, transport.ModeMarkdown)
+				}
 
-			// If updates are available, show update options
-			if result.NewVersion != "" {
-				message := fmt.Sprintf(
-					"%s\n\n"+
-						"*Current version:* `%s`\n"+
-						"*Available version:* `%s`\n\n"+
-						"Use `/update now` to install updates",
+				// Show success message
+				message := i18n.T(lang, "update.done",
 					result.Message,
-					strings.TrimSpace(result.OldVersion),
 					strings.TrimSpace(result.NewVersion),
+					result.BackupPath,
 				)
-				return c.Send(message, tele.ModeMarkdown)
-			}
 
-			return c.Send(result.Message, tele.ModeMarkdown)
-		}
+				c.Send(message, transport.ModeMarkdown)
 
-		// If argument is "now", perform update
-		if args[0] == "now" {
-			c.Send("🔄 Starting update process...", tele.ModeMarkdown)
+				// Restart ASB service, keeping the uptime
+				if err := watchdog.MarkRestart(); err != nil {
+					log.Printf("Failed to mark restart: %v", err)
+				}
+				restartMsg, restartErr := system.RestartASB(lang)
+				if restartErr != nil {
+					return c.Send(i18n.T(lang, "update.restart_manual", restartMsg), transport.ModeMarkdown)
+				}
 
-			// Perform update
-			result, err := system.PerformUpdate()
-			if err != nil {
-				return c.Send(fmt.Sprintf("❌ Update failed: %v", err), tele.ModeMarkdown)
+				return c.Send(restartMsg, transport.ModeMarkdown)
 			}
 
-			if !result.Success {
-				return c.Send(result.Message, tele.ModeMarkdown)
+			// Invalid argument
+			return c.Send(i18n.T(lang, "update.usage"), transport.ModeMarkdown)
+		},
+	})
+
+	r.registerAlertHandlers(watchdog)
+	r.registerScheduleHandlers(scheduler)
+	r.registerAliasHandlers(aliases)
+
+	// Language selection
+	r.RegisterButton("lang_set", config.RoleViewer, func(c transport.Context) error {
+		return r.setLanguage(c, c.Data())
+	})
+
+	r.Register(Command{
+		Name:        "lang",
+		Description: "cmd.lang",
+		Usage:       "/lang [en|ru]",
+		Role:        config.RoleViewer,
+		Handler: func(c transport.Context) error {
+			args := c.Args()
+			if len(args) > 0 {
+				return r.setLanguage(c, args[0])
 			}
 
-			// Show success message
-			message := fmt.Sprintf(
-				"%s\n\n"+
-					"*Updated to version:* `%s`\n"+
-					"Backup created at: `%s`\n\n"+
-					"Restarting ASB service now...",
-				result.Message,
-				strings.TrimSpace(result.NewVersion),
-				result.BackupPath,
-			)
-
-			c.Send(message, tele.ModeMarkdown)
-
-			// Restart ASB service
-			restartMsg, restartErr := system.RestartASB()
-			if restartErr != nil {
-				return c.Send(fmt.Sprintf("⚠️ %s\n\n%s", restartMsg, "Manual restart may be required."), tele.ModeMarkdown)
+			lang := r.lang(c)
+			var row []transport.Button
+			for _, l := range i18n.Supported() {
+				row = append(row, transport.Button{Text: i18n.T(l, "lang.name"), Unique: "lang_set", Data: string(l)})
 			}
+			return c.Send(i18n.T(lang, "lang.current", i18n.T(lang, "lang.name")), transport.ModeMarkdown, transport.Keyboard{row})
+		},
+	})
 
-			return c.Send(fmt.Sprintf("✅ %s", restartMsg), tele.ModeMarkdown)
+	return r
+}
+
+// runShell runs a shell command for /exec and aliases and sends its output
+func (r *Registry) runShell(c transport.Context, command string) error {
+	lang := r.lang(c)
+	c.Send(i18n.T(lang, "exec.running", command), transport.ModeMarkdown)
+
+	// Run the command
+	output, err := system.ExecuteCommand(command)
+	if errors.Is(err, context.DeadlineExceeded) {
+		output += i18n.T(lang, "exec.timeout")
+	}
+
+	// If output is empty, provide a fallback message
+	if strings.TrimSpace(output) == "" {
+		if err != nil {
+			output = i18n.T(lang, "exec.error", err)
+		} else {
+			output = i18n.T(lang, "exec.no_output")
 		}
+	}
 
-		// Invalid argument
-		return c.Send("Usage:\n• `/update` - Check for updates\n• `/update now` - Install available updates", tele.ModeMarkdown)
-	})
+	// Wrap output in code blocks for readability
+	return c.Send(i18n.T(lang, "exec.output", output), transport.ModeMarkdown)
+}
+
+// setLanguage stores the language preference of the sender and refreshes their menu
+func (r *Registry) setLanguage(c transport.Context, code string) error {
+	lang, ok := i18n.Parse(code)
+	if !ok {
+		return c.Send(i18n.T(r.lang(c), "lang.unknown", code), transport.ModeMarkdown)
+	}
+
+	if err := r.langs.Set(c.SenderID(), lang); err != nil {
+		return c.Send(i18n.T(lang, "lang.save_failed", err))
+	}
+	r.publishMenuFor(c.SenderID())
+
+	c.Respond(i18n.T(lang, "lang.name"))
+	return c.Send(i18n.T(lang, "lang.changed", i18n.T(lang, "lang.name")), transport.ModeMarkdown)
 }
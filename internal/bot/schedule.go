@@ -0,0 +1,136 @@
+package bot
+
+import (
+	"errors"
+	"strings"
+
+	"android-server-brain/config"
+	"android-server-brain/internal/i18n"
+	"android-server-brain/internal/system"
+	"android-server-brain/internal/transport"
+)
+
+// scheduleTimeFormat is used for next and last run times
+const scheduleTimeFormat = "Mon 02 Jan 15:04"
+
+// registerScheduleHandlers registers /schedule
+func (r *Registry) registerScheduleHandlers(scheduler *system.Scheduler) {
+	r.Register(Command{
+		Name:        "schedule",
+		Description: "cmd.schedule",
+		Usage:       "/schedule [list|add [--all] \"<cron>\" <command>|rm <id>]",
+		Role:        config.RoleAdmin,
+		Handler: func(c transport.Context) error {
+			lang := r.lang(c)
+			args := c.Args()
+			if len(args) == 0 || args[0] == "list" {
+				return c.Send(scheduleText(lang, scheduler.Tasks()), transport.ModeMarkdown)
+			}
+
+			switch args[0] {
+			case "add":
+				report := system.ReportFailures
+				rest := args[1:]
+				if len(rest) > 0 && rest[0] == "--all" {
+					report = system.ReportAll
+					rest = rest[1:]
+				}
+
+				spec, command, ok := splitCronArgs(strings.Join(rest, " "))
+				if !ok {
+					return c.Send(i18n.T(lang, "schedule.usage"), transport.ModeMarkdown)
+				}
+
+				task, err := scheduler.Add(spec, command, report, c.SenderID())
+				if err != nil {
+					return c.Send(i18n.T(lang, "schedule.bad_cron", spec, err), transport.ModeMarkdown)
+				}
+				return c.Send(i18n.T(lang, "schedule.added", task.ID, task.Cron, task.Command, task.Next.Format(scheduleTimeFormat)), transport.ModeMarkdown)
+
+			case "rm":
+				if len(args) != 2 {
+					return c.Send(i18n.T(lang, "schedule.usage"), transport.ModeMarkdown)
+				}
+				err := scheduler.Remove(args[1])
+				switch {
+				case errors.Is(err, system.ErrTaskNotFound):
+					return c.Send(i18n.T(lang, "schedule.not_found", args[1]), transport.ModeMarkdown)
+				case errors.Is(err, system.ErrConfigTask):
+					return c.Send(i18n.T(lang, "schedule.config_task", args[1]), transport.ModeMarkdown)
+				}
+				return c.Send(i18n.T(lang, "schedule.removed", args[1]), transport.ModeMarkdown)
+
+			default:
+				return c.Send(i18n.T(lang, "schedule.usage"), transport.ModeMarkdown)
+			}
+		},
+	})
+}
+
+// splitCronArgs splits `"0 3 * * *" cmd`, `@daily cmd` or an unquoted
+// `0 3 * * * cmd` into the cron expression and the command
+func splitCronArgs(s string) (spec, command string, ok bool) {
+	s = strings.TrimSpace(s)
+	switch {
+	case strings.HasPrefix(s, `"`):
+		end := strings.Index(s[1:], `"`)
+		if end < 0 {
+			return "", "", false
+		}
+		spec, command = s[1:end+1], s[end+2:]
+
+	case strings.HasPrefix(s, "@"):
+		fields := strings.SplitN(s, " ", 2)
+		if len(fields) != 2 {
+			return "", "", false
+		}
+		spec, command = fields[0], fields[1]
+
+	default:
+		fields := strings.Fields(s)
+		if len(fields) < 6 {
+			return "", "", false
+		}
+		spec = strings.Join(fields[:5], " ")
+		command = strings.Join(fields[5:], " ")
+	}
+
+	command = strings.TrimSpace(command)
+	return spec, command, spec != "" && command != ""
+}
+
+// scheduleText renders the task list with next and last runs
+func scheduleText(lang i18n.Lang, tasks []system.Task) string {
+	if len(tasks) == 0 {
+		return i18n.T(lang, "schedule.empty")
+	}
+
+	var b strings.Builder
+	b.WriteString(i18n.T(lang, "schedule.title"))
+	for _, t := range tasks {
+		var tags string
+		if t.Config {
+			tags += i18n.T(lang, "schedule.config_tag")
+		}
+		if t.Report == system.ReportAll {
+			tags += i18n.T(lang, "schedule.all_tag")
+		}
+		b.WriteString(i18n.T(lang, "schedule.line", t.ID, t.Cron, t.Command, tags))
+
+		next := i18n.T(lang, "schedule.never")
+		if !t.Next.IsZero() {
+			next = t.Next.Format(scheduleTimeFormat)
+		}
+		last := i18n.T(lang, "schedule.never")
+		switch {
+		case t.Running:
+			last = i18n.T(lang, "schedule.last_running")
+		case t.LastRun != nil && t.LastRun.Error != "":
+			last = i18n.T(lang, "schedule.last_failed", t.LastRun.Time.Format(scheduleTimeFormat))
+		case t.LastRun != nil:
+			last = i18n.T(lang, "schedule.last_ok", t.LastRun.Time.Format(scheduleTimeFormat))
+		}
+		b.WriteString(i18n.T(lang, "schedule.details", next, last))
+	}
+	return b.String()
+}
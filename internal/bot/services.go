@@ -0,0 +1,222 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"android-server-brain/config"
+	"android-server-brain/internal/i18n"
+	"android-server-brain/internal/system"
+	"android-server-brain/internal/transport"
+)
+
+// buttonService carries "<action>|<service>", e.g. "restart|sshd"
+const buttonService = "svc"
+
+// registerServiceHandlers registers /services, /svc and the service buttons
+func (r *Registry) registerServiceHandlers() {
+	r.Register(Command{
+		Name:        "services",
+		Description: "cmd.services",
+		Role:        config.RoleViewer,
+		Handler: func(c transport.Context) error {
+			text, kb := r.servicesView(r.lang(c), r.role(c))
+			return c.Send(text, transport.ModeMarkdown, kb)
+		},
+	})
+
+	r.Register(Command{
+		Name:        "svc",
+		Description: "cmd.svc",
+		Usage:       "/svc <start|stop|restart|enable|disable|status|logs> <name>",
+		Role:        config.RoleOperator,
+		Handler: func(c transport.Context) error {
+			lang := r.lang(c)
+			args := c.Args()
+			if len(args) != 2 {
+				return c.Send(i18n.T(lang, "svc.usage"), transport.ModeMarkdown)
+			}
+
+			action, name := args[0], args[1]
+			switch action {
+			case system.ActionStart, system.ActionStop, system.ActionRestart, system.ActionEnable, system.ActionDisable:
+				result, err := system.ControlService(action, name)
+				return c.Send(serviceResultText(lang, result, err), transport.ModeMarkdown)
+			case "status":
+				return c.Send(serviceStatusText(lang, name), transport.ModeMarkdown)
+			case "logs":
+				return c.Send(r.serviceLogText(lang, name, serviceLogLines), transport.ModeMarkdown)
+			default:
+				return c.Send(i18n.T(lang, "svc.usage"), transport.ModeMarkdown)
+			}
+		},
+	})
+
+	r.RegisterButton(buttonService, config.RoleViewer, func(c transport.Context) error {
+		lang := r.lang(c)
+		action, name, _ := strings.Cut(c.Data(), "|")
+
+		switch action {
+		case "logs":
+			return r.guard(config.RoleOperator, func(c transport.Context) error {
+				c.Respond("")
+				return c.Send(r.serviceLogText(lang, name, serviceLogLines), transport.ModeMarkdown)
+			})(c)
+
+		case system.ActionStart, system.ActionStop, system.ActionRestart:
+			return r.guard(config.RoleOperator, func(c transport.Context) error {
+				c.Respond(i18n.T(lang, "service.working"))
+				result, err := system.ControlService(action, name)
+				c.Send(serviceResultText(lang, result, err), transport.ModeMarkdown)
+
+				text, kb := r.servicesView(lang, r.role(c))
+				return c.Edit(text, transport.ModeMarkdown, kb)
+			})(c)
+
+		default:
+			// The name button refreshes the list
+			c.Respond("")
+			text, kb := r.servicesView(lang, r.role(c))
+			return c.Edit(text, transport.ModeMarkdown, kb)
+		}
+	})
+}
+
+// servicesView renders the service list and, for operators, a row of
+// action buttons per service
+func (r *Registry) servicesView(lang i18n.Lang, role config.Role) (string, transport.Keyboard) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	services, err := system.ListServices(ctx)
+	if err != nil {
+		return i18n.T(lang, "service.no_backend", err), nil
+	}
+	if len(services) == 0 {
+		return i18n.T(lang, "services.empty"), nil
+	}
+
+	var kb transport.Keyboard
+	for _, s := range services {
+		data := func(action string) string { return action + "|" + s.Name }
+		row := []transport.Button{{Text: serviceIcon(s.State) + " " + s.Name, Unique: buttonService, Data: data("status")}}
+		if role.Allows(config.RoleOperator) {
+			row = append(row,
+				transport.Button{Text: "▶️", Unique: buttonService, Data: data(system.ActionStart)},
+				transport.Button{Text: "⏹", Unique: buttonService, Data: data(system.ActionStop)},
+				transport.Button{Text: "🔄", Unique: buttonService, Data: data(system.ActionRestart)},
+				transport.Button{Text: "📜", Unique: buttonService, Data: data("logs")},
+			)
+		}
+		kb = append(kb, row)
+	}
+
+	text := servicesText(lang, services)
+	if role.Allows(config.RoleOperator) {
+		text += i18n.T(lang, "services.hint")
+	}
+	return text, kb
+}
+
+// servicesText lists services with their state, grouped by backend
+func servicesText(lang i18n.Lang, services []system.ServiceStatus) string {
+	var b strings.Builder
+	b.WriteString(i18n.T(lang, "services.title"))
+
+	backend := ""
+	for _, s := range services {
+		if s.Backend != backend {
+			backend = s.Backend
+			b.WriteString(i18n.T(lang, "services.backend", backend))
+		}
+
+		b.WriteString(serviceLine(lang, s))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// serviceLine describes the state of one service
+func serviceLine(lang i18n.Lang, s system.ServiceStatus) string {
+	var line string
+	switch s.State {
+	case system.ServiceUp:
+		line = i18n.T(lang, "services.up", s.Name, s.Since)
+	case system.ServiceDown:
+		line = i18n.T(lang, "services.down", s.Name, s.Since)
+	case system.ServiceFailed:
+		line = i18n.T(lang, "services.failed", s.Name, s.Since)
+	default:
+		line = i18n.T(lang, "services.unknown", s.Name)
+	}
+	if s.PID != 0 {
+		line += i18n.T(lang, "services.pid", s.PID)
+	}
+	if !s.Enabled {
+		line += i18n.T(lang, "services.disabled")
+	}
+	return line
+}
+
+// serviceStatusText renders the state of a single service
+func serviceStatusText(lang i18n.Lang, name string) string {
+	status, err := system.GetServiceStatus(name)
+	switch {
+	case errors.Is(err, system.ErrUnknownService):
+		return i18n.T(lang, "service.unknown", name)
+	case errors.Is(err, system.ErrNoServiceBackend):
+		return i18n.T(lang, "service.no_backend", err)
+	}
+
+	// An unknown state is still worth showing, with the reason below it
+	text := servicesText(lang, []system.ServiceStatus{status})
+	if err != nil {
+		text += i18n.T(lang, "svc.status_failed", err)
+	}
+	return text
+}
+
+// serviceResultText describes the outcome of a service action
+func serviceResultText(lang i18n.Lang, result system.ServiceResult, err error) string {
+	switch {
+	case errors.Is(err, system.ErrUnknownService):
+		return i18n.T(lang, "service.unknown", result.Service)
+	case errors.Is(err, system.ErrNoServiceBackend):
+		return i18n.T(lang, "service.no_backend", err)
+	}
+
+	var text string
+	switch {
+	case errors.Is(err, system.ErrAlreadyRunning):
+		text = i18n.T(lang, "service.running", result.Service)
+	case errors.Is(err, system.ErrNotRunning):
+		text = i18n.T(lang, "service.not_running", result.Service)
+	case err != nil:
+		text = i18n.T(lang, "service."+result.Action+"_failed", result.Service, err)
+	default:
+		text = i18n.T(lang, "service."+result.Action+"_ok", result.Service)
+	}
+	if result.Output != "" {
+		text += i18n.T(lang, "service.output", result.Output)
+	}
+	if result.Status != nil {
+		text += "\n" + serviceLine(lang, *result.Status)
+	}
+	return text
+}
+
+// serviceIcon returns the state marker used on buttons
+func serviceIcon(state string) string {
+	switch state {
+	case system.ServiceUp:
+		return "🟢"
+	case system.ServiceDown:
+		return "🔴"
+	case system.ServiceFailed:
+		return "❌"
+	default:
+		return "⚪️"
+	}
+}
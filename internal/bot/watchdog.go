@@ -0,0 +1,63 @@
+package bot
+
+import (
+	"time"
+
+	"android-server-brain/config"
+	"android-server-brain/internal/i18n"
+	"android-server-brain/internal/system"
+	"android-server-brain/internal/transport"
+)
+
+// minWatchdogInterval keeps /watchdog interval from hammering the device
+const minWatchdogInterval = 30 * time.Second
+
+// watchdogHandler shows the watchdog status; its subcommands control the
+// scheduler and require the operator role
+func (r *Registry) watchdogHandler(watchdog *system.Watchdog) transport.HandlerFunc {
+	return func(c transport.Context) error {
+		args := c.Args()
+		if len(args) == 0 {
+			return c.Send(watchdog.GetStatus(r.lang(c)), transport.ModeMarkdown)
+		}
+
+		return r.guard(config.RoleOperator, func(c transport.Context) error {
+			lang := r.lang(c)
+			switch args[0] {
+			case "pause":
+				watchdog.Pause()
+				return c.Send(i18n.T(lang, "watchdog.paused"), transport.ModeMarkdown)
+
+			case "resume":
+				watchdog.Resume()
+				return c.Send(i18n.T(lang, "watchdog.resumed"), transport.ModeMarkdown)
+
+			case "interval":
+				if len(args) != 2 {
+					return c.Send(i18n.T(lang, "watchdog.usage"), transport.ModeMarkdown)
+				}
+				d, err := time.ParseDuration(args[1])
+				if err != nil || d < minWatchdogInterval {
+					return c.Send(i18n.T(lang, "watchdog.bad_interval", args[1], minWatchdogInterval), transport.ModeMarkdown)
+				}
+				watchdog.SetInterval(d)
+				return c.Send(i18n.T(lang, "watchdog.interval_set", d), transport.ModeMarkdown)
+
+			case "run-now":
+				name := ""
+				if len(args) > 1 {
+					name = args[1]
+					if !watchdog.HasCheck(name) {
+						return c.Send(i18n.T(lang, "mute.unknown_check", name), transport.ModeMarkdown)
+					}
+				}
+				c.Send(i18n.T(lang, "watchdog.running"), transport.ModeMarkdown)
+				watchdog.RunNow(name)
+				return c.Send(watchdog.GetStatus(lang), transport.ModeMarkdown)
+
+			default:
+				return c.Send(i18n.T(lang, "watchdog.usage"), transport.ModeMarkdown)
+			}
+		})(c)
+	}
+}
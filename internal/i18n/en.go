@@ -0,0 +1,288 @@
+package i18n
+
+// en is the English message catalog and the fallback for missing translations
+var en = map[string]string{
+	"lang.name":        "🇬🇧 English",
+	"lang.current":     "🌐 Current language: %s\n\nChoose a language:",
+	"lang.unknown":     "❓ Unsupported language: `%s`. Use `/lang en` or `/lang ru`.",
+	"lang.save_failed": "❌ Failed to save language preference: %v",
+	"lang.changed":     "✅ Language set to %s",
+
+	"cmd.help":      "List commands or show help for one",
+	"cmd.start":     "Welcome message",
+	"cmd.status":    "View system health (battery, storage, uptime)",
+	"cmd.battery":   "Detailed battery status",
+	"cmd.watchdog":  "Watchdog monitoring status",
+	"cmd.exec":      "Execute a shell command",
+	"cmd.reboot":    "Reboot the device (with confirmation)",
+	"cmd.restart":   "Restart a service",
+	"cmd.services":  "Services with their state and controls",
+	"cmd.svc":       "Control a single service: start, stop, restart, enable, disable, status, logs",
+	"cmd.logs":      "Tail or follow a service log",
+	"cmd.update":    "Check for and install ASB updates",
+	"cmd.lang":      "Change the interface language",
+	"cmd.checks":    "Table of all health checks",
+	"cmd.mute":      "Silence alerts of a check for a while",
+	"cmd.unmute":    "Resume alerts of a muted check",
+	"cmd.digest":    "Preview the daily or weekly digest",
+	"cmd.du":        "Largest directories in the storage root",
+	"cmd.schedule":  "Manage scheduled commands",
+	"cmd.alias":     "Saved command aliases and favorites",
+	"cmd.run":       "Run a saved alias",
+	"cmd.dashboard": "Live overview with buttons",
+
+	"help.title":   "📖 *Available Commands*\n\n",
+	"help.footer":  "\nUse `/help <command>` for details.",
+	"help.command": "*/%s* - %s\n\nUsage: `%s`\nRequired role: %s",
+	"help.unknown": "❓ Unknown command: `%s`",
+
+	"access.denied_short": "⛔ Not allowed",
+	"access.denied":       "⛔ This command requires the *%s* role.",
+
+	"start.welcome": "Welcome to Android Server Brain. Use /status to check system health or /help to see all commands.",
+
+	"status.battery_unavailable": "Unavailable (API missing)",
+	"status.report":              "📊 *System Status*\n\n🔋 *Battery:* %s\n💾 *Free Space:* %s\n⏱ *Uptime:* %s",
+
+	"battery.unavailable": "❌ Battery info unavailable: %v",
+	"battery.report":      "🔋 *Battery Status*\n\nCharge: %.1f%%\nStatus: %s\nPlugged: %s\nHealth: %s\nTemperature: %.1f°C",
+
+	"watchdog.battery_warning":  "⚠️ *Low Battery Alert*\n\n🔋 Current charge: %.1f%%\n🌡 Temperature: %.1f°C\n🔌 Status: %s\n\nPlease connect charger!",
+	"watchdog.battery_critical": "🪫 *Critical Battery Level*\n\n🔋 Current charge: %.1f%%\n🌡 Temperature: %.1f°C\n🔌 Status: %s\n\nConnect the charger as soon as possible!",
+	"watchdog.battery_shutdown": "🚨 *Shutdown Imminent*\n\n🔋 Current charge: %.1f%%\n🌡 Temperature: %.1f°C\n🔌 Status: %s\n\nThe device will power off shortly unless it is charged now!",
+	"watchdog.battery_charged":  "🔌 *Battery Charged*\n\n🔋 Current charge: %.1f%%\n🌡 Temperature: %.1f°C\n🔌 Status: %s\n\nYou can unplug the charger to protect battery health.",
+	"watchdog.status":           "🐕 *Watchdog Status*\n\n⏱ Uptime: %v\n📅 Default interval: %v\n",
+	"watchdog.paused_line":      "⏸ *Paused* — use `/watchdog resume`\n",
+	"watchdog.paused":           "⏸ Watchdog paused. Checks won't run until `/watchdog resume`.",
+	"watchdog.resumed":          "▶️ Watchdog resumed.",
+	"watchdog.interval_set":     "📅 Default check interval set to %v (until restart).",
+	"watchdog.bad_interval":     "❓ Invalid interval: `%s`. Use a duration of at least %v, e.g. `5m`.",
+	"watchdog.running":          "⏳ Running checks...",
+	"watchdog.usage":            "Usage:\n• `/watchdog` - Status\n• `/watchdog pause` / `resume`\n• `/watchdog interval 5m`\n• `/watchdog run-now [check]`",
+	"watchdog.check_line":       "\n%s *%s* — %s\n   Last run: %s · Last alert: %s · Next: %s\n",
+
+	"check.restored":        "restored from the last run, waiting for the next",
+	"check.pending":         "not run yet",
+	"check.error":           "check failed: %v",
+	"check.battery_summary": "%.1f%%, %.1f°C, %s",
+	"check.disk_summary":    "%s",
+	"check.disk_path":       "%s %.0f%% used, %s free",
+	"check.disk_path_eta":   "%s %.0f%% used, %s free, full %s",
+	"check.disk_error":      "%s: %v",
+	"check.process_running": "running (pid %s)",
+	"check.process_down":    "not running",
+
+	"check.endpoint_error":           "%v",
+	"check.endpoint_status":          "HTTP %d",
+	"check.endpoint_status_expected": "HTTP %d, expected %d",
+	"check.endpoint_body":            "response does not contain %q",
+	"check.endpoint_slow":            "slow: %d ms (limit %d ms)",
+	"check.endpoint_http_ok":         "HTTP %d in %d ms",
+	"check.endpoint_tcp_ok":          "connected in %d ms",
+	"check.endpoint_tls":             "%s, certificate valid for %d days",
+	"check.endpoint_tls_expiring":    "certificate expires in %d days",
+	"check.endpoint_tls_expired":     "certificate expired",
+
+	"checks.title":  "🩺 *Health Checks*\n\n```\n%s```",
+	"checks.header": "CHECK\tSTATUS\tRAN AGO\tDETAILS",
+
+	"disk.eta_hours": "in ~%d hours",
+	"disk.eta_days":  "in ~%d days",
+
+	"time.never": "never",
+	"time.ago":   "%v ago",
+	"time.now":   "now",
+	"time.in":    "in %v",
+
+	"watchdog.temp_warning":            "🌡 *Battery Getting Hot*\n\n🌡 Temperature: %.1[2]f°C\n🔋 Charge: %.1[1]f%%\n🔌 Status: %[3]s\n\nConsider reducing load or improving cooling.",
+	"watchdog.temp_critical":           "🔥 *Battery Overheating*\n\n🌡 Temperature: %.1[2]f°C\n🔋 Charge: %.1[1]f%%\n🔌 Status: %[3]s\n\nReduce load or unplug the charger now!",
+	"watchdog.temp_normal":             "❄️ *Battery Temperature Normal*\n\n🌡 Temperature: %.1[2]f°C\n🔋 Charge: %.1[1]f%%",
+	"watchdog.health_bad":              "🩺 *Battery Health Alert*\n\nHealth: `%s`\n🌡 Temperature: %.1f°C\n🔋 Charge: %.1f%%\n\nCheck the battery and charger.",
+	"watchdog.health_good":             "🩺 *Battery Health Recovered*\n\nHealth is `GOOD` again (was `%s`).",
+	"watchdog.actions_overheat":        "🧯 *Overheat actions executed*\n\n%s",
+	"watchdog.actions_cooldown":        "▶️ *Cooldown actions executed*\n\n%s",
+	"watchdog.disk_warning":            "💾 *Disk Space Low*\n\n📂 `%s`\nUsed: %.0f%%\nFree: %s of %s\n\nConsider cleaning up old files.",
+	"watchdog.disk_critical":           "🚨 *Disk Almost Full*\n\n📂 `%s`\nUsed: %.0f%%\nFree: %s of %s\n\nFree up space now, services may start failing!",
+	"watchdog.disk_ok":                 "💾 *Disk Space Recovered*\n\n📂 `%s`\nUsed: %.0f%%\nFree: %s of %s",
+	"watchdog.disk_prediction":         "📈 *Disk Filling Up*\n\n📂 `%s`\nAt the current rate it will be full %s.\nGrowth: %s per hour\nFree: %s",
+	"watchdog.process_down":            "💀 *Process Down*\n\n⚙️ `%s` is not running.",
+	"watchdog.process_down_restarting": "💀 *Process Down*\n\n⚙️ `%s` is not running. Restarting it…",
+	"watchdog.process_up":              "✅ *Process Running Again*\n\n⚙️ `%s` (pid %s)",
+	"watchdog.process_restarted":       "🔁 *Process Restarted*\n\n⚙️ `%s` started with pid %d\nRestarts this hour: %d of %d\nNext attempt no sooner than in %v",
+	"watchdog.process_restart_failed":  "❌ *Process Restart Failed*\n\n⚙️ `%s`\nError: %v",
+	"watchdog.process_restart_limit":   "🛑 *Restart Limit Reached*\n\n⚙️ `%s` was restarted %d times in the last hour. Auto-restart is paused, please investigate.",
+	"watchdog.endpoint_critical":       "🔴 *Endpoint Down*\n\n🌐 *%s* `%s`\n%s",
+	"watchdog.endpoint_warning":        "⚠️ *Endpoint Degraded*\n\n🌐 *%s* `%s`\n%s",
+	"watchdog.endpoint_ok":             "✅ *Endpoint Recovered*\n\n🌐 *%s* `%s`\n%s",
+
+	"alert.ack_button": "👍 Acknowledge",
+	"alert.escalated":  "⏫ *Escalated* — not acknowledged for %v\n\n",
+	"alert.acked":      "👍 Alert acknowledged by %s.",
+	"alert.ack_stale":  "ℹ️ Already acknowledged or escalated",
+
+	"mute.snooze_button":   "😴 Snooze 1h",
+	"mute.tomorrow_button": "🔕 Mute until tomorrow",
+	"mute.done":            "🔕 Alerts of `%s` are muted until %s.",
+	"mute.done_short":      "🔕 Muted",
+	"mute.unknown_check":   "❓ Unknown check: `%s`. See /checks for the list.",
+	"mute.bad_duration":    "❓ Invalid duration: `%s`. Use e.g. `30m`, `2h`, `1d` or `tomorrow`.",
+	"mute.usage":           "Usage: `/mute <check>|all <duration>|tomorrow`\nExample: `/mute battery 2h`",
+	"mute.none":            "🔔 No checks are muted.",
+	"mute.list_title":      "🔕 *Muted checks*\n\n",
+	"mute.list_line":       "• `%s` until %s\n",
+	"mute.time_format":     "Jan 2 15:04",
+	"unmute.usage":         "Usage: `/unmute <check>|all`",
+	"unmute.not_muted":     "ℹ️ `%s` is not muted.",
+	"unmute.done":          "🔔 Alerts of `%s` are enabled again.",
+
+	"digest.daily":        "📰 *Daily Digest* — %s\n\n",
+	"digest.date":         "Jan 2",
+	"digest.weekly":       "📰 *Weekly Digest* — %s – %s\n\n",
+	"digest.uptime":       "⏱ ASB uptime: %v\n📱 Device: %s\n",
+	"digest.battery":      "🔋 Battery: %.0f–%.0f%%, %d charge cycles, peak %.1f°C\n",
+	"digest.battery_none": "🔋 Battery: no data\n",
+	"digest.disk":         "💾 `%s`: %s used (%s)\n",
+	"digest.execs":        "🖥 /exec commands: %d\n",
+	"digest.tasks":        "⏰ Scheduled task runs: %d\n",
+	"digest.restarts":     "🔁 Process restarts: %d\n",
+	"digest.failed":       "🩺 Failed checks: %s\n",
+	"digest.failed_none":  "🩺 Failed checks: none\n",
+	"digest.alerts":       "🔔 Alerts: %d sent, %d suppressed\n",
+
+	"du.title":   "💽 *Disk usage of* `%s`\n\nTotal: %s\n\n",
+	"du.line":    "`%9s`  `%s`\n",
+	"du.more":    "… and %d more\n",
+	"du.empty":   "The directory is empty.",
+	"du.outside": "❌ Cannot scan `%s`: %v",
+	"du.failed":  "❌ Failed to scan `%s`: %v",
+
+	"schedule.title":          "⏰ *Scheduled tasks*\n\n",
+	"schedule.empty":          "No scheduled tasks. Add one with `/schedule add \"0 3 * * *\" <command>`",
+	"schedule.line":           "`%s` `%s` — `%s`%s\n",
+	"schedule.details":        "    next: %s, last: %s\n",
+	"schedule.config_tag":     " _(config)_",
+	"schedule.all_tag":        " _(all output)_",
+	"schedule.never":          "never",
+	"schedule.last_ok":        "✅ %s",
+	"schedule.last_failed":    "❌ %s",
+	"schedule.last_running":   "⏳ running",
+	"schedule.usage":          "Usage:\n• `/schedule list`\n• `/schedule add [--all] \"<cron>\" <command>`\n• `/schedule rm <id>`\n\nFailures are reported by default, `--all` reports every run.\nExample: `/schedule add \"0 3 * * *\" backup.sh`",
+	"schedule.bad_cron":       "❌ Invalid cron expression `%s`: %v",
+	"schedule.added":          "✅ Task `%s` scheduled: `%s` — `%s`\nNext run: %s",
+	"schedule.removed":        "🗑 Task `%s` removed.",
+	"schedule.not_found":      "❌ No task `%s`. See `/schedule list`.",
+	"schedule.config_task":    "❌ Task `%s` is defined in config.json and can't be removed from chat.",
+	"schedule.no_output":      "(no output)",
+	"schedule.report_ok":      "✅ *Task* `%s` *finished* in %v\n`%s`\n```\n%s\n```",
+	"schedule.report_failed":  "❌ *Task* `%s` *failed* after %v: %v\n`%s`\n```\n%s\n```",
+	"schedule.report_timeout": "⌛ *Task* `%s` *timed out* after %v\n`%s`\n```\n%s\n```",
+
+	"alias.title":        "📌 *Aliases*\n\n",
+	"alias.line":         "%s`%s` → `%s`\n",
+	"alias.empty":        "No aliases yet. Add one with `/alias add logs \"tail -n 100 ~/app/log.txt\"`",
+	"alias.usage":        "Usage:\n• `/alias` - favorites keyboard\n• `/alias list`\n• `/alias add <name> \"<command>\"` - `$1`..`$9` take arguments, `$@` takes all\n• `/alias rm <name>`\n• `/alias fav|unfav <name>`\n• `/run <name> [args...]`",
+	"alias.bad_name":     "❌ Invalid alias name `%s`: use up to 32 lowercase letters, digits, `-` and `_`.",
+	"alias.added":        "✅ Alias `%s` saved: `%s`",
+	"alias.removed":      "🗑 Alias `%s` removed.",
+	"alias.unknown":      "❌ No alias `%s`. See `/alias list`.",
+	"alias.save_failed":  "❌ Failed to save aliases: %v",
+	"alias.favorited":    "⭐️ Alias `%s` added to favorites.",
+	"alias.unfavorited":  "Alias `%s` removed from favorites.",
+	"alias.favorites":    "⭐️ *Favorite aliases*\n\nTap to run:",
+	"alias.no_favorites": "No favorite aliases. Mark one with `/alias fav <name>`.",
+	"alias.bad_args":     "❌ Alias `%s` takes %d arguments, got %d.\n`%s`",
+
+	"dashboard.updated":    "\n\n🕒 _Updated %s_",
+	"dashboard.overview":   "🔄 Refresh",
+	"dashboard.battery":    "🔋 Battery",
+	"dashboard.disk":       "💾 Disk",
+	"dashboard.jobs":       "⏰ Jobs",
+	"dashboard.services":   "⚙️ Services",
+	"dashboard.reboot":     "🔁 Reboot",
+	"dashboard.back":       "⬅️ Back",
+	"dashboard.pin":        "📌 Pin and auto-refresh",
+	"dashboard.unpin":      "📍 Unpin",
+	"dashboard.stale":      "This dashboard is outdated, send /dashboard again",
+	"dashboard.pin_failed": "❌ Failed to pin the dashboard: %v",
+
+	"file.receiving":   "📥 Receiving file: %s...",
+	"file.save_failed": "❌ Error saving file: %v",
+	"file.saved":       "✅ File saved and made executable:\n`%s` \n\nYou can run it from `~/asb_files/%s`\n\nLocation: /storage/emulated/0/Download/asb_files/",
+
+	"exec.usage":     "Usage: `/exec <command>`",
+	"exec.running":   "⏳ Executing: `%s`...",
+	"exec.timeout":   "\n❌ Error: Command timed out",
+	"exec.error":     "Error: %v",
+	"exec.no_output": "Command executed successfully (no output).",
+	"exec.output":    "📝 *Output:*\n```\n%s\n```",
+
+	"reboot.confirm":        "⚠️ *System Reboot Confirmation*\n\nAre you sure you want to reboot the system? This will disconnect all active sessions.",
+	"reboot.confirm_button": "✅ YES - Reboot System",
+	"reboot.cancel_button":  "❌ NO - Cancel",
+	"reboot.cancelled":      "❌ Reboot cancelled.",
+	"reboot.failed":         "❌ Reboot command failed: %v\nOutput: %s",
+	"reboot.initiated":      "🔄 System reboot initiated...",
+
+	"restart.running": "⏳ Restarting service: `%s`...",
+
+	"service.no_backend":     "❌ Cannot manage services: %v",
+	"service.unknown":        "❌ Unknown service `%s`. See /services.",
+	"service.start_ok":       "✅ Service `%s` started",
+	"service.stop_ok":        "⏹ Service `%s` stopped",
+	"service.restart_ok":     "✅ Service `%s` restarted successfully",
+	"service.enable_ok":      "✅ Service `%s` will start automatically",
+	"service.disable_ok":     "⏸ Service `%s` will no longer start automatically",
+	"service.start_failed":   "❌ Failed to start `%s`: %v",
+	"service.stop_failed":    "❌ Failed to stop `%s`: %v",
+	"service.restart_failed": "❌ Failed to restart `%s`: %v",
+	"service.enable_failed":  "❌ Failed to enable `%s`: %v",
+	"service.disable_failed": "❌ Failed to disable `%s`: %v",
+	"service.running":        "ℹ️ Service `%s` is already running",
+	"service.not_running":    "ℹ️ Service `%s` is not running",
+	"service.output":         "\nOutput: %s",
+	"service.working":        "⏳ Working...",
+
+	"services.title":      "⚙️ *Services*\n\n",
+	"services.backend":    "_%s_\n",
+	"services.up":         "🟢 `%s` up %v",
+	"services.down":       "🔴 `%s` down %v",
+	"services.failed":     "❌ `%s` failed to start %v",
+	"services.unknown":    "⚪️ `%s` unknown",
+	"services.pid":        ", pid %d",
+	"services.disabled":   ", not started automatically",
+	"services.empty":      "No services found. termux-services looks for them in `$PREFIX/var/service`.",
+	"services.hint":       "\n▶️ start, ⏹ stop, 🔄 restart, 📜 logs",
+	"services.logs":       "📜 *%s* — last %d lines\n```\n%s\n```",
+	"services.no_logs":    "📜 The log of `%s` is empty.",
+	"services.log_failed": "❌ Failed to read the log of `%s`: %v",
+
+	"logs.usage":              "Usage: `/logs <service> [lines]` (1-500, default 30) or `/logs <service> follow`",
+	"logs.following":          "📜 *%s* — following for %d min\n```\n%s\n```",
+	"logs.follow_ended":       "📜 *%s* — no longer following\n```\n%s\n```",
+	"logs.follow_unsupported": "❌ Following logs needs a chat where messages can be edited, use `/logs <service>`.",
+	"logs.stop":               "⏹ Stop following",
+
+	"svc.usage":         "Usage: `/svc <start|stop|restart|enable|disable|status|logs> <name>`\nSee /services for the names.",
+	"svc.status_failed": "⚠️ %v\n",
+
+	"update.checking":       "🔍 Checking for updates...",
+	"update.check_error":    "❌ Error checking for updates: %v",
+	"update.offer":          "%s\n\n*Current version:* `%s`\n*Available version:* `%s`\n\nUse `/update now` to install updates",
+	"update.starting":       "🔄 Starting update process...",
+	"update.failed":         "❌ Update failed: %v",
+	"update.done":           "%s\n\n*Updated to version:* `%s`\nBackup created at: `%s`\n\nRestarting ASB service now...",
+	"update.restart_manual": "⚠️ %s\n\nManual restart may be required.",
+	"update.usage":          "Usage:\n• `/update` - Check for updates\n• `/update now` - Install available updates",
+	"update.no_workdir":     "❌ Failed to get working directory: %v",
+	"update.not_git":        "❌ Not a git repository. Cannot check for updates.",
+	"update.fetch_failed":   "❌ Failed to fetch updates: %v\nOutput: %s",
+	"update.status_failed":  "❌ Failed to check status: %v\nOutput: %s",
+	"update.available":      "✅ Updates are available!",
+	"update.up_to_date":     "✅ Already up to date. No updates available.",
+	"update.backup_failed":  "❌ Failed to create backup: %v",
+	"update.pull_failed":    "❌ Update failed: %v\nOutput: %s",
+	"update.success":        "✅ Successfully updated!\nOutput: %s",
+	"update.kill_failed":    "⚠️ Could not terminate old ASB process: %v\nOutput: %s",
+	"update.start_failed":   "❌ Failed to start new ASB process: %v",
+	"update.restarted":      "✅ ASB restarted successfully with new version!",
+}
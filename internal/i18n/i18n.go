@@ -0,0 +1,168 @@
+package i18n
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"android-server-brain/internal/storage"
+)
+
+// Lang is a supported interface language
+type Lang string
+
+const (
+	English Lang = "en"
+	Russian Lang = "ru"
+
+	// Default is used when neither a preference nor a client language is known
+	Default = English
+)
+
+// catalogs maps a language to its message templates
+var catalogs = map[Lang]map[string]string{
+	English: en,
+	Russian: ru,
+}
+
+// Supported returns all languages with a catalog
+func Supported() []Lang {
+	return []Lang{English, Russian}
+}
+
+// Parse maps a language code such as "ru-RU" to a supported language
+func Parse(code string) (Lang, bool) {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if i := strings.IndexAny(code, "-_"); i > 0 {
+		code = code[:i]
+	}
+	if _, ok := catalogs[Lang(code)]; ok {
+		return Lang(code), true
+	}
+	return Default, false
+}
+
+// T formats the message key in the given language, falling back to
+// English and finally to the key itself
+func T(lang Lang, key string, args ...interface{}) string {
+	tmpl, ok := catalogs[lang][key]
+	if !ok {
+		tmpl, ok = catalogs[Default][key]
+	}
+	if !ok {
+		log.Printf("i18n: missing message %q", key)
+		tmpl = key
+	}
+	if len(args) == 0 {
+		return tmpl
+	}
+	return fmt.Sprintf(tmpl, args...)
+}
+
+// Store keeps per-user language preferences in a JSON file
+type Store struct {
+	path string
+
+	mu      sync.RWMutex
+	chosen  map[int64]Lang // set with /lang
+	clients map[int64]Lang // last known client language
+}
+
+// storeFile is the format of the preferences file
+type storeFile struct {
+	Chosen  map[int64]Lang `json:"chosen"`
+	Clients map[int64]Lang `json:"clients"`
+}
+
+// NewStore loads preferences from path; an empty path keeps them in memory only
+func NewStore(path string) *Store {
+	s := &Store{path: path, chosen: make(map[int64]Lang), clients: make(map[int64]Lang)}
+	if path == "" {
+		return s
+	}
+
+	var file storeFile
+	if err := storage.LoadJSON(path, &file); err != nil {
+		log.Printf("Failed to load language preferences: %v", err)
+		return s
+	}
+	if file.Chosen == nil && file.Clients == nil {
+		// Older files only hold the /lang choices, keyed by user ID
+		if err := storage.LoadJSON(path, &s.chosen); err != nil {
+			log.Printf("Failed to load language preferences: %v", err)
+		}
+		return s
+	}
+	for id, lang := range file.Chosen {
+		s.chosen[id] = lang
+	}
+	for id, lang := range file.Clients {
+		s.clients[id] = lang
+	}
+	return s
+}
+
+// Get returns the preferred language of a user: the /lang choice, then the
+// client language code, then the last client language seen, then Default.
+// Messages sent without an update pass an empty client code.
+func (s *Store) Get(userID int64, clientCode string) Lang {
+	if s != nil {
+		s.mu.RLock()
+		lang, ok := s.chosen[userID]
+		s.mu.RUnlock()
+		if ok {
+			return lang
+		}
+	}
+	if lang, ok := Parse(clientCode); ok {
+		return lang
+	}
+	if s != nil {
+		s.mu.RLock()
+		lang, ok := s.clients[userID]
+		s.mu.RUnlock()
+		if ok {
+			return lang
+		}
+	}
+	return Default
+}
+
+// Set stores the preferred language of a user
+func (s *Store) Set(userID int64, lang Lang) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.chosen[userID] = lang
+	return s.save()
+}
+
+// Remember records the client language of a user so messages sent without
+// an update use it too. It reports whether the language of a user without a
+// /lang choice changed.
+func (s *Store) Remember(userID int64, clientCode string) bool {
+	lang, ok := Parse(clientCode)
+	if s == nil || !ok {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if previous, ok := s.clients[userID]; ok && previous == lang {
+		return false
+	}
+	s.clients[userID] = lang
+	if err := s.save(); err != nil {
+		log.Printf("Failed to save language preferences: %v", err)
+	}
+	_, chosen := s.chosen[userID]
+	return !chosen
+}
+
+// save writes the preferences, the caller holds mu
+func (s *Store) save() error {
+	if s.path == "" {
+		return nil
+	}
+	return storage.SaveJSON(s.path, storeFile{Chosen: s.chosen, Clients: s.clients})
+}
@@ -0,0 +1,86 @@
+package i18n
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStoreRemembersClientLanguage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "languages.json")
+	s := NewStore(path)
+
+	if got := s.Get(1, ""); got != Default {
+		t.Errorf("unknown user: got %s, want %s", got, Default)
+	}
+	if !s.Remember(1, "ru-RU") {
+		t.Error("first client language should be reported as a change")
+	}
+	if s.Remember(1, "ru") {
+		t.Error("same client language reported as a change")
+	}
+	if got := s.Get(1, ""); got != Russian {
+		t.Errorf("without an update: got %s, want the client language", got)
+	}
+	if got := s.Get(1, "en"); got != English {
+		t.Errorf("live client code: got %s, want it to win over the remembered one", got)
+	}
+
+	// A /lang choice beats the client language and is not overridden
+	s.Set(2, English)
+	if s.Remember(2, "ru") {
+		t.Error("client language of a user with a /lang choice reported as a change")
+	}
+	if got := s.Get(2, "ru"); got != English {
+		t.Errorf("/lang choice: got %s, want %s", got, English)
+	}
+
+	reloaded := NewStore(path)
+	if reloaded.Get(1, "") != Russian || reloaded.Get(2, "") != English {
+		t.Errorf("reloaded store lost preferences: %s, %s", reloaded.Get(1, ""), reloaded.Get(2, ""))
+	}
+}
+
+func TestStoreLoadsOldFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "languages.json")
+	if err := os.WriteFile(path, []byte(`{"7": "ru"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewStore(path)
+	if got := s.Get(7, "en"); got != Russian {
+		t.Errorf("got %s, want the stored /lang choice", got)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		code string
+		want Lang
+		ok   bool
+	}{
+		{"ru", Russian, true},
+		{"ru-RU", Russian, true},
+		{" EN_us ", English, true},
+		{"de", Default, false},
+		{"", Default, false},
+	}
+	for _, tt := range tests {
+		if got, ok := Parse(tt.code); got != tt.want || ok != tt.ok {
+			t.Errorf("Parse(%q) = %s, %v; want %s, %v", tt.code, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestCatalogsHaveTheSameKeys(t *testing.T) {
+	for key := range en {
+		if _, ok := ru[key]; !ok {
+			t.Errorf("ru is missing %q", key)
+		}
+	}
+	for key := range ru {
+		if _, ok := en[key]; !ok {
+			t.Errorf("en is missing %q", key)
+		}
+	}
+}
@@ -0,0 +1,288 @@
+package i18n
+
+// ru is the Russian message catalog
+var ru = map[string]string{
+	"lang.name":        "🇷🇺 Русский",
+	"lang.current":     "🌐 Текущий язык: %s\n\nВыберите язык:",
+	"lang.unknown":     "❓ Язык не поддерживается: `%s`. Используйте `/lang en` или `/lang ru`.",
+	"lang.save_failed": "❌ Не удалось сохранить выбор языка: %v",
+	"lang.changed":     "✅ Язык изменён: %s",
+
+	"cmd.help":      "Список команд или справка по команде",
+	"cmd.start":     "Приветственное сообщение",
+	"cmd.status":    "Состояние системы (батарея, память, аптайм)",
+	"cmd.battery":   "Подробное состояние батареи",
+	"cmd.watchdog":  "Состояние мониторинга watchdog",
+	"cmd.exec":      "Выполнить команду оболочки",
+	"cmd.reboot":    "Перезагрузить устройство (с подтверждением)",
+	"cmd.restart":   "Перезапустить службу",
+	"cmd.services":  "Службы, их состояние и управление",
+	"cmd.svc":       "Управление службой: start, stop, restart, enable, disable, status, logs",
+	"cmd.logs":      "Просмотр и отслеживание журнала службы",
+	"cmd.update":    "Проверить и установить обновления ASB",
+	"cmd.lang":      "Сменить язык интерфейса",
+	"cmd.checks":    "Таблица всех проверок",
+	"cmd.mute":      "Временно отключить уведомления проверки",
+	"cmd.unmute":    "Вернуть уведомления проверки",
+	"cmd.digest":    "Показать ежедневную или еженедельную сводку",
+	"cmd.du":        "Самые большие папки в хранилище",
+	"cmd.schedule":  "Управление командами по расписанию",
+	"cmd.alias":     "Сохранённые команды и избранное",
+	"cmd.run":       "Запустить сохранённую команду",
+	"cmd.dashboard": "Панель состояния с кнопками",
+
+	"help.title":   "📖 *Доступные команды*\n\n",
+	"help.footer":  "\nИспользуйте `/help <команда>` для подробностей.",
+	"help.command": "*/%s* - %s\n\nИспользование: `%s`\nТребуемая роль: %s",
+	"help.unknown": "❓ Неизвестная команда: `%s`",
+
+	"access.denied_short": "⛔ Нет доступа",
+	"access.denied":       "⛔ Для этой команды нужна роль *%s*.",
+
+	"start.welcome": "Добро пожаловать в Android Server Brain. Используйте /status для проверки состояния системы или /help для списка команд.",
+
+	"status.battery_unavailable": "Недоступно (нет API)",
+	"status.report":              "📊 *Состояние системы*\n\n🔋 *Батарея:* %s\n💾 *Свободно:* %s\n⏱ *Аптайм:* %s",
+
+	"battery.unavailable": "❌ Информация о батарее недоступна: %v",
+	"battery.report":      "🔋 *Состояние батареи*\n\nЗаряд: %.1f%%\nСтатус: %s\nПодключение: %s\nЗдоровье: %s\nТемпература: %.1f°C",
+
+	"watchdog.battery_warning":  "⚠️ *Низкий заряд батареи*\n\n🔋 Текущий заряд: %.1f%%\n🌡 Температура: %.1f°C\n🔌 Статус: %s\n\nПодключите зарядное устройство!",
+	"watchdog.battery_critical": "🪫 *Критический заряд батареи*\n\n🔋 Текущий заряд: %.1f%%\n🌡 Температура: %.1f°C\n🔌 Статус: %s\n\nПодключите зарядное устройство как можно скорее!",
+	"watchdog.battery_shutdown": "🚨 *Скорое отключение*\n\n🔋 Текущий заряд: %.1f%%\n🌡 Температура: %.1f°C\n🔌 Статус: %s\n\nУстройство скоро выключится, если не поставить его на зарядку!",
+	"watchdog.battery_charged":  "🔌 *Батарея заряжена*\n\n🔋 Текущий заряд: %.1f%%\n🌡 Температура: %.1f°C\n🔌 Статус: %s\n\nМожно отключить зарядку, чтобы сберечь батарею.",
+	"watchdog.status":           "🐕 *Состояние watchdog*\n\n⏱ Время работы: %v\n📅 Интервал по умолчанию: %v\n",
+	"watchdog.paused_line":      "⏸ *Приостановлен* — `/watchdog resume` для продолжения\n",
+	"watchdog.paused":           "⏸ Watchdog приостановлен. Проверки не запускаются до `/watchdog resume`.",
+	"watchdog.resumed":          "▶️ Watchdog возобновлён.",
+	"watchdog.interval_set":     "📅 Интервал проверок по умолчанию: %v (до перезапуска).",
+	"watchdog.bad_interval":     "❓ Неверный интервал: `%s`. Укажите длительность не меньше %v, например `5m`.",
+	"watchdog.running":          "⏳ Выполнение проверок...",
+	"watchdog.usage":            "Использование:\n• `/watchdog` - Состояние\n• `/watchdog pause` / `resume`\n• `/watchdog interval 5m`\n• `/watchdog run-now [проверка]`",
+	"watchdog.check_line":       "\n%s *%s* — %s\n   Запуск: %s · Тревога: %s · Следующий: %s\n",
+
+	"check.restored":        "восстановлено после перезапуска, ждёт следующего запуска",
+	"check.pending":         "ещё не запускалась",
+	"check.error":           "ошибка проверки: %v",
+	"check.battery_summary": "%.1f%%, %.1f°C, %s",
+	"check.disk_summary":    "%s",
+	"check.disk_path":       "%s занято %.0f%%, свободно %s",
+	"check.disk_path_eta":   "%s занято %.0f%%, свободно %s, заполнится %s",
+	"check.disk_error":      "%s: %v",
+	"check.process_running": "работает (pid %s)",
+	"check.process_down":    "не запущен",
+
+	"check.endpoint_error":           "%v",
+	"check.endpoint_status":          "HTTP %d",
+	"check.endpoint_status_expected": "HTTP %d, ожидался %d",
+	"check.endpoint_body":            "ответ не содержит %q",
+	"check.endpoint_slow":            "медленно: %d мс (лимит %d мс)",
+	"check.endpoint_http_ok":         "HTTP %d за %d мс",
+	"check.endpoint_tcp_ok":          "соединение за %d мс",
+	"check.endpoint_tls":             "%s, сертификат действует ещё %d дн.",
+	"check.endpoint_tls_expiring":    "сертификат истекает через %d дн.",
+	"check.endpoint_tls_expired":     "сертификат истёк",
+
+	"checks.title":  "🩺 *Проверки*\n\n```\n%s```",
+	"checks.header": "ПРОВЕРКА\tСТАТУС\tНАЗАД\tДЕТАЛИ",
+
+	"disk.eta_hours": "через ~%d ч",
+	"disk.eta_days":  "через ~%d дн.",
+
+	"time.never": "никогда",
+	"time.ago":   "%v назад",
+	"time.now":   "сейчас",
+	"time.in":    "через %v",
+
+	"watchdog.temp_warning":            "🌡 *Батарея нагревается*\n\n🌡 Температура: %.1[2]f°C\n🔋 Заряд: %.1[1]f%%\n🔌 Статус: %[3]s\n\nСнизьте нагрузку или улучшите охлаждение.",
+	"watchdog.temp_critical":           "🔥 *Перегрев батареи*\n\n🌡 Температура: %.1[2]f°C\n🔋 Заряд: %.1[1]f%%\n🔌 Статус: %[3]s\n\nСрочно снизьте нагрузку или отключите зарядку!",
+	"watchdog.temp_normal":             "❄️ *Температура батареи в норме*\n\n🌡 Температура: %.1[2]f°C\n🔋 Заряд: %.1[1]f%%",
+	"watchdog.health_bad":              "🩺 *Проблема с батареей*\n\nСостояние: `%s`\n🌡 Температура: %.1f°C\n🔋 Заряд: %.1f%%\n\nПроверьте батарею и зарядное устройство.",
+	"watchdog.health_good":             "🩺 *Состояние батареи восстановлено*\n\nСостояние снова `GOOD` (было `%s`).",
+	"watchdog.actions_overheat":        "🧯 *Выполнены действия при перегреве*\n\n%s",
+	"watchdog.actions_cooldown":        "▶️ *Выполнены действия после охлаждения*\n\n%s",
+	"watchdog.disk_warning":            "💾 *Мало места на диске*\n\n📂 `%s`\nЗанято: %.0f%%\nСвободно: %s из %s\n\nУдалите ненужные файлы.",
+	"watchdog.disk_critical":           "🚨 *Диск почти заполнен*\n\n📂 `%s`\nЗанято: %.0f%%\nСвободно: %s из %s\n\nСрочно освободите место, службы могут начать падать!",
+	"watchdog.disk_ok":                 "💾 *Место на диске в норме*\n\n📂 `%s`\nЗанято: %.0f%%\nСвободно: %s из %s",
+	"watchdog.disk_prediction":         "📈 *Диск заполняется*\n\n📂 `%s`\nПри текущей скорости он заполнится %s.\nРост: %s в час\nСвободно: %s",
+	"watchdog.process_down":            "💀 *Процесс остановлен*\n\n⚙️ `%s` не запущен.",
+	"watchdog.process_down_restarting": "💀 *Процесс остановлен*\n\n⚙️ `%s` не запущен. Перезапускаю…",
+	"watchdog.process_up":              "✅ *Процесс снова работает*\n\n⚙️ `%s` (pid %s)",
+	"watchdog.process_restarted":       "🔁 *Процесс перезапущен*\n\n⚙️ `%s` запущен с pid %d\nПерезапусков за час: %d из %d\nСледующая попытка не раньше чем через %v",
+	"watchdog.process_restart_failed":  "❌ *Не удалось перезапустить процесс*\n\n⚙️ `%s`\nОшибка: %v",
+	"watchdog.process_restart_limit":   "🛑 *Достигнут лимит перезапусков*\n\n⚙️ `%s` перезапускался %d раз за последний час. Автоперезапуск приостановлен, проверьте процесс.",
+	"watchdog.endpoint_critical":       "🔴 *Сервис недоступен*\n\n🌐 *%s* `%s`\n%s",
+	"watchdog.endpoint_warning":        "⚠️ *Сервис работает с проблемами*\n\n🌐 *%s* `%s`\n%s",
+	"watchdog.endpoint_ok":             "✅ *Сервис восстановлен*\n\n🌐 *%s* `%s`\n%s",
+
+	"alert.ack_button": "👍 Принято",
+	"alert.escalated":  "⏫ *Эскалация* — нет подтверждения уже %v\n\n",
+	"alert.acked":      "👍 Уведомление принял(а) %s.",
+	"alert.ack_stale":  "ℹ️ Уже подтверждено или эскалировано",
+
+	"mute.snooze_button":   "😴 Отложить на 1 ч",
+	"mute.tomorrow_button": "🔕 Тишина до завтра",
+	"mute.done":            "🔕 Уведомления `%s` отключены до %s.",
+	"mute.done_short":      "🔕 Отключено",
+	"mute.unknown_check":   "❓ Неизвестная проверка: `%s`. Список: /checks",
+	"mute.bad_duration":    "❓ Неверная длительность: `%s`. Например: `30m`, `2h`, `1d` или `tomorrow`.",
+	"mute.usage":           "Использование: `/mute <проверка>|all <длительность>|tomorrow`\nПример: `/mute battery 2h`",
+	"mute.none":            "🔔 Нет отключённых проверок.",
+	"mute.list_title":      "🔕 *Отключённые проверки*\n\n",
+	"mute.list_line":       "• `%s` до %s\n",
+	"mute.time_format":     "02.01 15:04",
+	"unmute.usage":         "Использование: `/unmute <проверка>|all`",
+	"unmute.not_muted":     "ℹ️ `%s` не отключена.",
+	"unmute.done":          "🔔 Уведомления `%s` снова включены.",
+
+	"digest.daily":        "📰 *Сводка за день* — %s\n\n",
+	"digest.date":         "02.01",
+	"digest.weekly":       "📰 *Сводка за неделю* — %s – %s\n\n",
+	"digest.uptime":       "⏱ Время работы ASB: %v\n📱 Устройство: %s\n",
+	"digest.battery":      "🔋 Батарея: %.0f–%.0f%%, циклов зарядки: %d, максимум %.1f°C\n",
+	"digest.battery_none": "🔋 Батарея: нет данных\n",
+	"digest.disk":         "💾 `%s`: занято %s (%s)\n",
+	"digest.execs":        "🖥 Команд /exec: %d\n",
+	"digest.tasks":        "⏰ Запусков задач по расписанию: %d\n",
+	"digest.restarts":     "🔁 Перезапусков процессов: %d\n",
+	"digest.failed":       "🩺 Сбои проверок: %s\n",
+	"digest.failed_none":  "🩺 Сбои проверок: нет\n",
+	"digest.alerts":       "🔔 Уведомления: отправлено %d, подавлено %d\n",
+
+	"du.title":   "💽 *Использование диска* `%s`\n\nВсего: %s\n\n",
+	"du.line":    "`%9s`  `%s`\n",
+	"du.more":    "… и ещё %d\n",
+	"du.empty":   "Папка пуста.",
+	"du.outside": "❌ Невозможно просканировать `%s`: %v",
+	"du.failed":  "❌ Не удалось просканировать `%s`: %v",
+
+	"schedule.title":          "⏰ *Задачи по расписанию*\n\n",
+	"schedule.empty":          "Задач нет. Добавьте: `/schedule add \"0 3 * * *\" <команда>`",
+	"schedule.line":           "`%s` `%s` — `%s`%s\n",
+	"schedule.details":        "    следующий: %s, последний: %s\n",
+	"schedule.config_tag":     " _(config)_",
+	"schedule.all_tag":        " _(весь вывод)_",
+	"schedule.never":          "никогда",
+	"schedule.last_ok":        "✅ %s",
+	"schedule.last_failed":    "❌ %s",
+	"schedule.last_running":   "⏳ выполняется",
+	"schedule.usage":          "Использование:\n• `/schedule list`\n• `/schedule add [--all] \"<cron>\" <команда>`\n• `/schedule rm <id>`\n\nПо умолчанию сообщается только об ошибках, `--all` присылает вывод каждого запуска.\nПример: `/schedule add \"0 3 * * *\" backup.sh`",
+	"schedule.bad_cron":       "❌ Неверное cron-выражение `%s`: %v",
+	"schedule.added":          "✅ Задача `%s` запланирована: `%s` — `%s`\nСледующий запуск: %s",
+	"schedule.removed":        "🗑 Задача `%s` удалена.",
+	"schedule.not_found":      "❌ Задачи `%s` нет. См. `/schedule list`.",
+	"schedule.config_task":    "❌ Задача `%s` задана в config.json и не может быть удалена из чата.",
+	"schedule.no_output":      "(нет вывода)",
+	"schedule.report_ok":      "✅ *Задача* `%s` *выполнена* за %v\n`%s`\n```\n%s\n```",
+	"schedule.report_failed":  "❌ *Задача* `%s` *завершилась ошибкой* через %v: %v\n`%s`\n```\n%s\n```",
+	"schedule.report_timeout": "⌛ *Задача* `%s` *прервана по таймауту* через %v\n`%s`\n```\n%s\n```",
+
+	"alias.title":        "📌 *Сохранённые команды*\n\n",
+	"alias.line":         "%s`%s` → `%s`\n",
+	"alias.empty":        "Сохранённых команд нет. Добавьте: `/alias add logs \"tail -n 100 ~/app/log.txt\"`",
+	"alias.usage":        "Использование:\n• `/alias` - клавиатура избранного\n• `/alias list`\n• `/alias add <имя> \"<команда>\"` - `$1`..`$9` принимают аргументы, `$@` — все\n• `/alias rm <имя>`\n• `/alias fav|unfav <имя>`\n• `/run <имя> [аргументы...]`",
+	"alias.bad_name":     "❌ Неверное имя `%s`: до 32 строчных латинских букв, цифр, `-` и `_`.",
+	"alias.added":        "✅ Команда `%s` сохранена: `%s`",
+	"alias.removed":      "🗑 Команда `%s` удалена.",
+	"alias.unknown":      "❌ Команды `%s` нет. См. `/alias list`.",
+	"alias.save_failed":  "❌ Не удалось сохранить команды: %v",
+	"alias.favorited":    "⭐️ Команда `%s` добавлена в избранное.",
+	"alias.unfavorited":  "Команда `%s` убрана из избранного.",
+	"alias.favorites":    "⭐️ *Избранные команды*\n\nНажмите, чтобы запустить:",
+	"alias.no_favorites": "Избранных команд нет. Отметьте: `/alias fav <имя>`.",
+	"alias.bad_args":     "❌ Команда `%s` принимает аргументов: %d, передано: %d.\n`%s`",
+
+	"dashboard.updated":    "\n\n🕒 _Обновлено %s_",
+	"dashboard.overview":   "🔄 Обновить",
+	"dashboard.battery":    "🔋 Батарея",
+	"dashboard.disk":       "💾 Диск",
+	"dashboard.jobs":       "⏰ Задачи",
+	"dashboard.services":   "⚙️ Сервисы",
+	"dashboard.reboot":     "🔁 Перезагрузка",
+	"dashboard.back":       "⬅️ Назад",
+	"dashboard.pin":        "📌 Закрепить и обновлять",
+	"dashboard.unpin":      "📍 Открепить",
+	"dashboard.stale":      "Панель устарела, отправьте /dashboard ещё раз",
+	"dashboard.pin_failed": "❌ Не удалось закрепить панель: %v",
+
+	"file.receiving":   "📥 Получение файла: %s...",
+	"file.save_failed": "❌ Ошибка сохранения файла: %v",
+	"file.saved":       "✅ Файл сохранён и сделан исполняемым:\n`%s` \n\nЗапускать можно из `~/asb_files/%s`\n\nРасположение: /storage/emulated/0/Download/asb_files/",
+
+	"exec.usage":     "Использование: `/exec <команда>`",
+	"exec.running":   "⏳ Выполнение: `%s`...",
+	"exec.timeout":   "\n❌ Ошибка: превышено время выполнения команды",
+	"exec.error":     "Ошибка: %v",
+	"exec.no_output": "Команда выполнена успешно (нет вывода).",
+	"exec.output":    "📝 *Вывод:*\n```\n%s\n```",
+
+	"reboot.confirm":        "⚠️ *Подтверждение перезагрузки*\n\nВы уверены, что хотите перезагрузить систему? Все активные сессии будут разорваны.",
+	"reboot.confirm_button": "✅ ДА - Перезагрузить",
+	"reboot.cancel_button":  "❌ НЕТ - Отмена",
+	"reboot.cancelled":      "❌ Перезагрузка отменена.",
+	"reboot.failed":         "❌ Команда перезагрузки не выполнена: %v\nВывод: %s",
+	"reboot.initiated":      "🔄 Перезагрузка системы запущена...",
+
+	"restart.running": "⏳ Перезапуск службы: `%s`...",
+
+	"service.no_backend":     "❌ Управление службами недоступно: %v",
+	"service.unknown":        "❌ Неизвестная служба `%s`. См. /services.",
+	"service.start_ok":       "✅ Служба `%s` запущена",
+	"service.stop_ok":        "⏹ Служба `%s` остановлена",
+	"service.restart_ok":     "✅ Служба `%s` успешно перезапущена",
+	"service.enable_ok":      "✅ Служба `%s` будет запускаться автоматически",
+	"service.disable_ok":     "⏸ Служба `%s` больше не будет запускаться автоматически",
+	"service.start_failed":   "❌ Не удалось запустить `%s`: %v",
+	"service.stop_failed":    "❌ Не удалось остановить `%s`: %v",
+	"service.restart_failed": "❌ Не удалось перезапустить `%s`: %v",
+	"service.enable_failed":  "❌ Не удалось включить автозапуск `%s`: %v",
+	"service.disable_failed": "❌ Не удалось отключить автозапуск `%s`: %v",
+	"service.running":        "ℹ️ Служба `%s` уже запущена",
+	"service.not_running":    "ℹ️ Служба `%s` не запущена",
+	"service.output":         "\nВывод: %s",
+	"service.working":        "⏳ Выполняется...",
+
+	"services.title":      "⚙️ *Службы*\n\n",
+	"services.backend":    "_%s_\n",
+	"services.up":         "🟢 `%s` работает %v",
+	"services.down":       "🔴 `%s` остановлена %v",
+	"services.failed":     "❌ `%s` не запустилась %v",
+	"services.unknown":    "⚪️ `%s` состояние неизвестно",
+	"services.pid":        ", pid %d",
+	"services.disabled":   ", не запускается автоматически",
+	"services.empty":      "Службы не найдены. termux-services ищет их в `$PREFIX/var/service`.",
+	"services.hint":       "\n▶️ запуск, ⏹ остановка, 🔄 перезапуск, 📜 журнал",
+	"services.logs":       "📜 *%s* — последние %d строк\n```\n%s\n```",
+	"services.no_logs":    "📜 Журнал `%s` пуст.",
+	"services.log_failed": "❌ Не удалось прочитать журнал `%s`: %v",
+
+	"logs.usage":              "Использование: `/logs <служба> [строк]` (1-500, по умолчанию 30) или `/logs <служба> follow`",
+	"logs.following":          "📜 *%s* — отслеживание в течение %d мин\n```\n%s\n```",
+	"logs.follow_ended":       "📜 *%s* — отслеживание завершено\n```\n%s\n```",
+	"logs.follow_unsupported": "❌ Для отслеживания журнала нужен чат, где можно редактировать сообщения, используйте `/logs <служба>`.",
+	"logs.stop":               "⏹ Остановить",
+
+	"svc.usage":         "Использование: `/svc <start|stop|restart|enable|disable|status|logs> <имя>`\nИмена служб см. в /services.",
+	"svc.status_failed": "⚠️ %v\n",
+
+	"update.checking":       "🔍 Проверка обновлений...",
+	"update.check_error":    "❌ Ошибка проверки обновлений: %v",
+	"update.offer":          "%s\n\n*Текущая версия:* `%s`\n*Доступная версия:* `%s`\n\nИспользуйте `/update now` для установки",
+	"update.starting":       "🔄 Запуск обновления...",
+	"update.failed":         "❌ Обновление не удалось: %v",
+	"update.done":           "%s\n\n*Обновлено до версии:* `%s`\nРезервная копия: `%s`\n\nПерезапуск службы ASB...",
+	"update.restart_manual": "⚠️ %s\n\nМожет потребоваться ручной перезапуск.",
+	"update.usage":          "Использование:\n• `/update` - Проверить обновления\n• `/update now` - Установить обновления",
+	"update.no_workdir":     "❌ Не удалось определить рабочую директорию: %v",
+	"update.not_git":        "❌ Это не git-репозиторий. Проверка обновлений невозможна.",
+	"update.fetch_failed":   "❌ Не удалось получить обновления: %v\nВывод: %s",
+	"update.status_failed":  "❌ Не удалось проверить статус: %v\nВывод: %s",
+	"update.available":      "✅ Доступны обновления!",
+	"update.up_to_date":     "✅ Установлена последняя версия. Обновлений нет.",
+	"update.backup_failed":  "❌ Не удалось создать резервную копию: %v",
+	"update.pull_failed":    "❌ Обновление не удалось: %v\nВывод: %s",
+	"update.success":        "✅ Обновление выполнено!\nВывод: %s",
+	"update.kill_failed":    "⚠️ Не удалось завершить старый процесс ASB: %v\nВывод: %s",
+	"update.start_failed":   "❌ Не удалось запустить новый процесс ASB: %v",
+	"update.restarted":      "✅ ASB успешно перезапущен с новой версией!",
+}
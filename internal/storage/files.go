@@ -5,28 +5,22 @@ import (
 	"os"
 	"path/filepath"
 
-	tele "gopkg.in/telebot.v3"
+	"android-server-brain/internal/transport"
 )
 
-// SaveTelegramFile downloads a file from Telegram and saves it to the asb_files directory
-func SaveTelegramFile(b *tele.Bot, doc *tele.Document, targetDir string) (string, error) {
-	// Get the file path from Telegram servers
-	file, err := b.FileByID(doc.FileID)
-	if err != nil {
-		return "", fmt.Errorf("failed to get file by ID: %v", err)
-	}
-
+// SaveFile downloads a file received by the frontend and saves it to the asb_files directory
+func SaveFile(fetcher transport.Fetcher, doc *transport.Document, targetDir string) (string, error) {
 	// Prepare the full destination path in ~/asb_files (symlinked to /storage/emulated/0/Download/asb_files)
 	home, _ := os.UserHomeDir()
 	asbFilesDir := filepath.Join(home, "asb_files")
 
 	// Ensure the directory exists
-	err = os.MkdirAll(asbFilesDir, 0755)
+	err := os.MkdirAll(asbFilesDir, 0755)
 	if err != nil {
 		return "", fmt.Errorf("failed to create asb_files directory: %v", err)
 	}
 
-	fullPath := filepath.Join(asbFilesDir, doc.FileName)
+	fullPath := filepath.Join(asbFilesDir, filepath.Base(doc.FileName))
 
 	// Create the file on disk
 	out, err := os.Create(fullPath)
@@ -35,8 +29,8 @@ func SaveTelegramFile(b *tele.Bot, doc *tele.Document, targetDir string) (string
 	}
 	defer out.Close()
 
-	// Download the file directly using telebot
-	err = b.Download(&file, fullPath)
+	// Download the file through the frontend
+	err = fetcher.Download(doc, fullPath)
 	if err != nil {
 		return "", fmt.Errorf("failed to download file: %v", err)
 	}
@@ -0,0 +1,201 @@
+package system
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"android-server-brain/config"
+	"android-server-brain/internal/i18n"
+	"android-server-brain/internal/transport"
+)
+
+// ButtonAck acknowledges an alert and cancels its escalation; the button
+// data is the notification ID
+const ButtonAck = "alert_ack"
+
+// sinkTimeout bounds a single delivery attempt
+const sinkTimeout = 30 * time.Second
+
+// Notification is an alert on its way to the sinks
+type Notification struct {
+	ID       string
+	Check    string
+	Severity CheckStatus
+	Message  Message
+	Time     time.Time
+
+	Ackable   bool          // will be escalated unless acknowledged
+	Escalated time.Duration // unacknowledged for this long, zero for the first delivery
+}
+
+// Sink delivers notifications to one destination
+type Sink interface {
+	Send(ctx context.Context, n Notification) error
+}
+
+// route sends notifications of at least min severity to a sink
+type route struct {
+	name string
+	min  CheckStatus
+	sink Sink
+}
+
+// AlertRouter delivers alerts to the configured sinks by severity and
+// escalates unacknowledged ones to a second recipient
+type AlertRouter struct {
+	routes        []route
+	escalation    *route
+	escalateAfter time.Duration
+
+	mu      sync.Mutex
+	seq     int
+	pending map[string]*time.Timer // notification ID -> escalation timer
+}
+
+// NewAlertRouter builds the routes from config; without routes everything
+// goes to AdminID over Telegram
+func NewAlertRouter(notifier transport.Notifier, cfg *config.Config, langs *i18n.Store) *AlertRouter {
+	r := &AlertRouter{pending: make(map[string]*time.Timer)}
+
+	routes := cfg.Alerts.Routes
+	if len(routes) == 0 {
+		routes = []config.AlertRoute{{Name: "admin", Type: "telegram", MinSeverity: "ok", ChatID: cfg.AdminID}}
+	}
+	for _, rc := range routes {
+		r.routes = append(r.routes, newRoute(rc, notifier, langs))
+	}
+
+	if e := cfg.Alerts.Escalation; e != nil {
+		escalation := newRoute(e.Route, notifier, langs)
+		escalation.min = parseSeverity(e.MinSeverity)
+		r.escalation = &escalation
+		r.escalateAfter = time.Duration(e.AfterMinutes) * time.Minute
+	}
+	return r
+}
+
+// newRoute creates the sink of a configured route
+func newRoute(rc config.AlertRoute, notifier transport.Notifier, langs *i18n.Store) route {
+	var sink Sink
+	switch rc.Type {
+	case "webhook":
+		sink = newWebhookSink(rc.URL)
+	case "smtp":
+		sink = &smtpSink{config: rc.SMTP}
+	default:
+		sink = &telegramSink{notifier: notifier, langs: langs, chatID: rc.ChatID}
+	}
+	return route{name: rc.Name, min: parseSeverity(rc.MinSeverity), sink: sink}
+}
+
+// parseSeverity maps a config severity name to a status
+func parseSeverity(s string) CheckStatus {
+	switch s {
+	case "warning":
+		return StatusWarning
+	case "critical":
+		return StatusCritical
+	default:
+		return StatusOK
+	}
+}
+
+// Route delivers the alerts of a check and reports whether any sink accepted one
+func (r *AlertRouter) Route(check string, alerts []Alert) bool {
+	delivered := false
+	for _, alert := range alerts {
+		n := Notification{
+			ID:       r.nextID(),
+			Check:    check,
+			Severity: alert.Severity,
+			Message:  alert.Message,
+			Time:     time.Now(),
+			Ackable:  r.escalation != nil && alert.Severity >= r.escalation.min,
+		}
+
+		sent := false
+		for _, rt := range r.routes {
+			if n.Severity < rt.min {
+				continue
+			}
+			if r.send(rt, n) {
+				sent = true
+			}
+		}
+
+		if sent && n.Ackable {
+			r.schedule(n)
+		}
+		delivered = delivered || sent
+	}
+	return delivered
+}
+
+// send delivers a notification to one route
+func (r *AlertRouter) send(rt route, n Notification) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), sinkTimeout)
+	defer cancel()
+
+	if err := rt.sink.Send(ctx, n); err != nil {
+		log.Printf("Failed to send %s alert to %s: %v", n.Check, rt.name, err)
+		return false
+	}
+	log.Printf("Sent %s alert to %s: %s", n.Check, rt.name, n.Message.Key)
+	return true
+}
+
+// nextID returns a unique notification ID
+func (r *AlertRouter) nextID() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.seq++
+	return strconv.FormatInt(time.Now().Unix(), 36) + "-" + strconv.Itoa(r.seq)
+}
+
+// schedule escalates the notification unless it is acknowledged in time
+func (r *AlertRouter) schedule(n Notification) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.pending[n.ID] = time.AfterFunc(r.escalateAfter, func() {
+		r.mu.Lock()
+		_, ok := r.pending[n.ID]
+		delete(r.pending, n.ID)
+		r.mu.Unlock()
+		if !ok {
+			return
+		}
+
+		n.Ackable = false
+		n.Escalated = r.escalateAfter
+		r.send(*r.escalation, n)
+	})
+}
+
+// Acknowledge cancels the escalation of a notification and reports
+// whether it was still pending
+func (r *AlertRouter) Acknowledge(id string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	timer, ok := r.pending[id]
+	if ok {
+		timer.Stop()
+		delete(r.pending, id)
+	}
+	return ok
+}
+
+// Stop cancels all pending escalations
+func (r *AlertRouter) Stop() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, timer := range r.pending {
+		timer.Stop()
+		delete(r.pending, id)
+	}
+}
@@ -0,0 +1,222 @@
+package system
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"android-server-brain/config"
+)
+
+// BatteryLevel is the severity of a low battery condition
+type BatteryLevel int
+
+const (
+	BatteryOK BatteryLevel = iota
+	BatteryWarning
+	BatteryCritical
+	BatteryShutdown
+)
+
+func (l BatteryLevel) String() string {
+	switch l {
+	case BatteryWarning:
+		return "warning"
+	case BatteryCritical:
+		return "critical"
+	case BatteryShutdown:
+		return "shutdown"
+	default:
+		return "ok"
+	}
+}
+
+// BatteryCheck watches charge level, temperature and health of the battery
+type BatteryCheck struct {
+	config   config.BatteryConfig
+	interval time.Duration
+
+	// read is the battery source, replaceable for tests
+	read func() (*BatteryStatus, error)
+
+	batteryLevel    BatteryLevel // last alerted low battery level
+	chargedNotified bool         // "charged to N%" notice already sent
+	tempLevel       TempLevel    // last alerted temperature level
+	lastTempAlert   time.Time
+	overheated      bool   // overheat actions ran, cooldown actions pending
+	lastHealth      string // last reported battery health
+}
+
+// NewBatteryCheck creates the built-in battery check
+func NewBatteryCheck(cfg config.BatteryConfig, interval time.Duration) *BatteryCheck {
+	return &BatteryCheck{
+		config:   cfg,
+		interval: interval,
+		read:     getBatteryStatus,
+	}
+}
+
+// Name implements Check
+func (c *BatteryCheck) Name() string { return "battery" }
+
+// Interval implements Check
+func (c *BatteryCheck) Interval() time.Duration { return c.interval }
+
+// Run implements Check
+func (c *BatteryCheck) Run(ctx context.Context) CheckResult {
+	battery, err := c.read()
+	if err != nil {
+		return CheckResult{
+			Status:  StatusUnknown,
+			Summary: Msg("check.error", err),
+		}
+	}
+
+	var alerts []Alert
+	alerts = append(alerts, c.checkCharge(battery)...)
+	alerts = append(alerts, c.checkTemperature(ctx, battery)...)
+	alerts = append(alerts, c.checkHealth(battery)...)
+
+	status := StatusOK
+	switch {
+	case c.batteryLevel >= BatteryCritical || c.tempLevel == TempCritical:
+		status = StatusCritical
+	case c.batteryLevel == BatteryWarning || c.tempLevel == TempWarning || c.lastHealth != "GOOD" && c.lastHealth != "":
+		status = StatusWarning
+	}
+
+	charging := 0.0
+	if battery.IsCharging() {
+		charging = 1
+	}
+
+	return CheckResult{
+		Status:  status,
+		Summary: Msg("check.battery_summary", battery.Percentage, battery.Temperature, battery.Status),
+		Alerts:  alerts,
+		Metrics: Metrics{
+			MetricBatteryPercent:  battery.Percentage,
+			MetricBatteryTemp:     battery.Temperature,
+			MetricBatteryCharging: charging,
+		},
+	}
+}
+
+// batteryCheckState is the persisted alert state of BatteryCheck
+type batteryCheckState struct {
+	BatteryLevel    BatteryLevel `json:"battery_level"`
+	ChargedNotified bool         `json:"charged_notified"`
+	TempLevel       TempLevel    `json:"temp_level"`
+	LastTempAlert   time.Time    `json:"last_temp_alert"`
+	Overheated      bool         `json:"overheated"`
+	LastHealth      string       `json:"last_health"`
+}
+
+// SaveState implements StatefulCheck
+func (c *BatteryCheck) SaveState() interface{} {
+	return batteryCheckState{
+		BatteryLevel:    c.batteryLevel,
+		ChargedNotified: c.chargedNotified,
+		TempLevel:       c.tempLevel,
+		LastTempAlert:   c.lastTempAlert,
+		Overheated:      c.overheated,
+		LastHealth:      c.lastHealth,
+	}
+}
+
+// RestoreState implements StatefulCheck
+func (c *BatteryCheck) RestoreState(data json.RawMessage) error {
+	var s batteryCheckState
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	c.batteryLevel = s.BatteryLevel
+	c.chargedNotified = s.ChargedNotified
+	c.tempLevel = s.TempLevel
+	c.lastTempAlert = s.LastTempAlert
+	// State saved before the flag existed was overheated while critical
+	c.overheated = s.Overheated || s.TempLevel == TempCritical
+	c.lastHealth = s.LastHealth
+	return nil
+}
+
+// checkCharge alerts once per low battery level and when charged
+func (c *BatteryCheck) checkCharge(battery *BatteryStatus) []Alert {
+	thresholds := c.config
+
+	if battery.IsCharging() {
+		// Charging clears low battery alerts
+		c.batteryLevel = BatteryOK
+
+		// Tell the admin to unplug once the charge limit is reached
+		if thresholds.ChargedPercent > 0 && battery.Percentage >= thresholds.ChargedPercent && !c.chargedNotified {
+			c.chargedNotified = true
+			return []Alert{batteryAlert(StatusOK, "watchdog.battery_charged", battery)}
+		}
+		return nil
+	}
+
+	// Unplugged: re-arm the charged notice
+	c.chargedNotified = false
+
+	level := evaluateBatteryLevel(thresholds, c.batteryLevel, battery.Percentage)
+	previous := c.batteryLevel
+	c.batteryLevel = level
+
+	// Alert only when the battery gets worse, once per level
+	if level > previous {
+		severity := StatusWarning
+		if level >= BatteryCritical {
+			severity = StatusCritical
+		}
+		return []Alert{batteryAlert(severity, "watchdog.battery_"+level.String(), battery)}
+	}
+	return nil
+}
+
+// batteryAlert builds an alert whose message takes charge, temperature and status
+func batteryAlert(severity CheckStatus, key string, battery *BatteryStatus) Alert {
+	return Alert{
+		Severity: severity,
+		Message:  Msg(key, battery.Percentage, battery.Temperature, battery.Status),
+	}
+}
+
+// evaluateBatteryLevel maps a charge percentage to an alert level. A level
+// is only left again once the charge rises above its threshold plus the
+// hysteresis margin, so readings around a boundary don't cause flapping.
+func evaluateBatteryLevel(t config.BatteryConfig, current BatteryLevel, percentage float64) BatteryLevel {
+	level := BatteryOK
+	switch {
+	case percentage < t.ShutdownPercent:
+		level = BatteryShutdown
+	case percentage < t.CriticalPercent:
+		level = BatteryCritical
+	case percentage < t.WarningPercent:
+		level = BatteryWarning
+	}
+
+	if level >= current {
+		return level
+	}
+
+	// Recovering: step down only past the hysteresis margin of each level
+	for current > level && percentage >= thresholdFor(t, current)+t.Hysteresis {
+		current--
+	}
+	return current
+}
+
+// thresholdFor returns the upper bound of a battery level
+func thresholdFor(t config.BatteryConfig, level BatteryLevel) float64 {
+	switch level {
+	case BatteryShutdown:
+		return t.ShutdownPercent
+	case BatteryCritical:
+		return t.CriticalPercent
+	case BatteryWarning:
+		return t.WarningPercent
+	default:
+		return 100
+	}
+}
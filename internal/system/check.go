@@ -0,0 +1,147 @@
+package system
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"android-server-brain/internal/i18n"
+)
+
+// CheckStatus is the outcome of a single health check run
+type CheckStatus int
+
+const (
+	StatusUnknown CheckStatus = iota
+	StatusOK
+	StatusWarning
+	StatusCritical
+)
+
+func (s CheckStatus) String() string {
+	switch s {
+	case StatusOK:
+		return "ok"
+	case StatusWarning:
+		return "warning"
+	case StatusCritical:
+		return "critical"
+	default:
+		return "unknown"
+	}
+}
+
+// Icon returns the emoji used for the status in messages
+func (s CheckStatus) Icon() string {
+	switch s {
+	case StatusOK:
+		return "✅"
+	case StatusWarning:
+		return "⚠️"
+	case StatusCritical:
+		return "🔴"
+	default:
+		return "❔"
+	}
+}
+
+// Message is a localizable text: a catalog key and its format arguments.
+// Checks produce messages so they can be rendered in each recipient's language.
+type Message struct {
+	Key  string
+	Args []interface{}
+}
+
+// Msg builds a Message
+func Msg(key string, args ...interface{}) Message {
+	return Message{Key: key, Args: args}
+}
+
+// Render formats the message in the given language. Arguments that are
+// messages themselves are rendered first; a []Message is joined with "; ".
+func (m Message) Render(lang i18n.Lang) string {
+	if m.Key == "" {
+		return ""
+	}
+
+	args := make([]interface{}, len(m.Args))
+	for i, arg := range m.Args {
+		switch v := arg.(type) {
+		case Message:
+			args[i] = v.Render(lang)
+		case []Message:
+			parts := make([]string, len(v))
+			for j, part := range v {
+				parts[j] = part.Render(lang)
+			}
+			args[i] = strings.Join(parts, "; ")
+		default:
+			args[i] = arg
+		}
+	}
+	return i18n.T(lang, m.Key, args...)
+}
+
+// Alert is a notification raised by a check
+type Alert struct {
+	Severity CheckStatus
+	Message  Message
+}
+
+// CheckResult is what a check reports after each run
+type CheckResult struct {
+	Status  CheckStatus
+	Summary Message // one line shown in /watchdog
+	Alerts  []Alert // notifications to deliver, usually only on state changes
+	Metrics Metrics // readings collected into the daily statistics
+}
+
+// Metric names reported by the built-in checks
+const (
+	MetricBatteryPercent  = "battery.percent"
+	MetricBatteryTemp     = "battery.temperature"
+	MetricBatteryCharging = "battery.charging" // 1 while charging
+	MetricDiskUsedPrefix  = "disk.used:"       // followed by the path, in bytes
+	MetricRestarts        = "process.restarts"
+)
+
+// Metrics are numeric readings of a check run
+type Metrics map[string]float64
+
+// Check is a periodic health check run by the watchdog. Implementations
+// keep their own alert state (levels, hysteresis, cooldowns) between runs
+// and only return alerts when something worth notifying happened.
+type Check interface {
+	// Name identifies the check in /watchdog and commands
+	Name() string
+	// Interval is the time between runs; zero uses the watchdog default
+	Interval() time.Duration
+	// Run evaluates the check once
+	Run(ctx context.Context) CheckResult
+}
+
+// checkState is the scheduling state the watchdog keeps for every check
+type checkState struct {
+	check      Check
+	interval   time.Duration
+	running    bool
+	lastRun    time.Time
+	nextRun    time.Time
+	lastResult CheckResult
+	lastAlert  time.Time
+	state      json.RawMessage // last saved StatefulCheck state
+
+	usesDefault bool // follows the watchdog default interval
+}
+
+// CheckInfo is a snapshot of a check for status displays
+type CheckInfo struct {
+	Name      string
+	Interval  time.Duration
+	Status    CheckStatus
+	Summary   Message
+	LastRun   time.Time
+	LastAlert time.Time
+	NextRun   time.Time
+}
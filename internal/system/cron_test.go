@@ -0,0 +1,82 @@
+package system
+
+import (
+	"testing"
+	"time"
+)
+
+// at parses a UTC time in the "2006-01-02 15:04" layout
+func at(t *testing.T, s string) time.Time {
+	t.Helper()
+	v, err := time.Parse("2006-01-02 15:04", s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestCronNext(t *testing.T) {
+	tests := []struct {
+		spec string
+		from string
+		want []string // consecutive runs after from
+	}{
+		{"*/15 * * * *", "2026-03-10 08:07", []string{"2026-03-10 08:15", "2026-03-10 08:30", "2026-03-10 08:45", "2026-03-10 09:00"}},
+		{"0 1-5/2 * * *", "2026-03-10 00:30", []string{"2026-03-10 01:00", "2026-03-10 03:00", "2026-03-10 05:00", "2026-03-11 01:00"}},
+		{"5,10,50 9 * * *", "2026-03-10 09:10", []string{"2026-03-10 09:50", "2026-03-11 09:05"}},
+		{"30 6 * jan,jul *", "2026-03-10 00:00", []string{"2026-07-01 06:30", "2026-07-02 06:30"}},
+		{"0 9 * * MON-FRI", "2026-03-13 10:00", []string{"2026-03-16 09:00", "2026-03-17 09:00"}}, // Friday to Monday
+		{"0 0 * * 7", "2026-03-10 00:00", []string{"2026-03-15 00:00"}},                           // 7 is Sunday
+		// Both day fields restricted: the 1st of the month or any Monday
+		{"0 0 1 * mon", "2026-03-24 12:00", []string{"2026-03-30 00:00", "2026-04-01 00:00", "2026-04-06 00:00"}},
+		// Only one restricted: the other "*" doesn't widen the match
+		{"0 0 1 * *", "2026-03-24 12:00", []string{"2026-04-01 00:00"}},
+		{"@daily", "2026-03-10 08:07", []string{"2026-03-11 00:00", "2026-03-12 00:00"}},
+		{"@hourly", "2026-03-10 08:00", []string{"2026-03-10 09:00", "2026-03-10 10:00"}},
+		// Month and year boundaries
+		{"0 12 31 * *", "2026-01-31 12:00", []string{"2026-03-31 12:00", "2026-05-31 12:00"}},
+		{"0 0 29 feb *", "2026-01-01 00:00", []string{"2028-02-29 00:00"}},
+		{"59 23 31 dec *", "2026-12-31 23:59", []string{"2027-12-31 23:59"}},
+		{"*/30 * * * *", "2026-12-31 23:45", []string{"2027-01-01 00:00", "2027-01-01 00:30"}},
+	}
+
+	for _, tt := range tests {
+		s, err := ParseCron(tt.spec)
+		if err != nil {
+			t.Errorf("%s: %v", tt.spec, err)
+			continue
+		}
+		next := at(t, tt.from)
+		for _, want := range tt.want {
+			next = s.Next(next)
+			if !next.Equal(at(t, want)) {
+				t.Errorf("%s: got %s, want %s", tt.spec, next.Format("2006-01-02 15:04"), want)
+				break
+			}
+		}
+	}
+}
+
+func TestCronRejectsInvalid(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * 32 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"* * * foo *",
+		"* * * * mon-",
+		"@often",
+		"0 0 30 feb *", // never matches
+	} {
+		if _, err := ParseCron(spec); err == nil {
+			t.Errorf("%q: want an error", spec)
+		}
+	}
+}
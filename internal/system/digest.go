@@ -0,0 +1,171 @@
+package system
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"android-server-brain/config"
+	"android-server-brain/internal/i18n"
+	"android-server-brain/internal/transport"
+)
+
+// Digest sends the scheduled daily and weekly summary reports to all admins
+type Digest struct {
+	watchdog *Watchdog
+	notifier transport.Notifier
+	langs    *i18n.Store
+	config   *config.Config
+}
+
+// NewDigest creates the digest reporter for the watchdog's data
+func NewDigest(notifier transport.Notifier, cfg *config.Config, langs *i18n.Store, watchdog *Watchdog) *Digest {
+	return &Digest{
+		watchdog: watchdog,
+		notifier: notifier,
+		langs:    langs,
+		config:   cfg,
+	}
+}
+
+// Start runs the digest schedule until ctx is cancelled
+func (d *Digest) Start(ctx context.Context) {
+	if !d.config.Digest.Daily && !d.config.Digest.Weekly {
+		return
+	}
+
+	go func() {
+		for {
+			next, weekly := nextDigest(time.Now(), d.config.Digest)
+			log.Printf("Next digest at %s (weekly: %t)", next.Format(time.RFC3339), weekly)
+
+			timer := time.NewTimer(time.Until(next))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+				d.send(weekly)
+			}
+		}
+	}()
+}
+
+// nextDigest returns the next digest time after now and whether it is the
+// weekly one; on the weekly day it replaces the daily digest
+func nextDigest(now time.Time, cfg config.DigestConfig) (time.Time, bool) {
+	local := now.In(cfg.Location())
+	hour, minute := cfg.At()
+	at := time.Date(local.Year(), local.Month(), local.Day(), hour, minute, 0, 0, cfg.Location())
+
+	for i := 0; i <= 7; i++ {
+		t := at.AddDate(0, 0, i)
+		if !t.After(now) {
+			continue
+		}
+		if cfg.Weekly && t.Weekday() == cfg.Day() {
+			return t, true
+		}
+		if cfg.Daily {
+			return t, false
+		}
+	}
+	// Unreachable when at least one digest is enabled
+	return at.AddDate(0, 0, 7), cfg.Weekly
+}
+
+// send delivers the digest to every admin in their language
+func (d *Digest) send(weekly bool) {
+	now := time.Now()
+	for _, id := range d.config.AdminIDs() {
+		lang := d.langs.Get(id, "")
+		if _, err := d.notifier.Notify(id, d.Report(lang, weekly, now), transport.ModeMarkdown); err != nil {
+			log.Printf("Failed to send digest to %d: %v", id, err)
+		}
+	}
+	log.Printf("Sent digest (weekly: %t)", weekly)
+}
+
+// Report renders the digest for the last full day, or the last seven full
+// days for the weekly digest, before now
+func (d *Digest) Report(lang i18n.Lang, weekly bool, now time.Time) string {
+	local := now.In(d.config.Digest.Location())
+	to := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location())
+	from := to.AddDate(0, 0, -1)
+	layout := i18n.T(lang, "digest.date")
+	title := i18n.T(lang, "digest.daily", from.Format(layout))
+	if weekly {
+		from = to.AddDate(0, 0, -7)
+		title = i18n.T(lang, "digest.weekly", from.Format(layout), to.AddDate(0, 0, -1).Format(layout))
+	}
+
+	stats := d.watchdog.Stats(from, to)
+
+	var b strings.Builder
+	b.WriteString(title)
+	b.WriteString(i18n.T(lang, "digest.uptime", time.Since(d.watchdog.StartTime()).Round(time.Minute), deviceUptime()))
+
+	if stats.BatterySamples > 0 {
+		b.WriteString(i18n.T(lang, "digest.battery", stats.BatteryMin, stats.BatteryMax, stats.ChargeCycles, stats.TempMax))
+	} else {
+		b.WriteString(i18n.T(lang, "digest.battery_none"))
+	}
+
+	paths := make([]string, 0, len(stats.DiskLast))
+	for path := range stats.DiskLast {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		b.WriteString(i18n.T(lang, "digest.disk", path, formatBytes(uint64(stats.DiskLast[path])), formatChange(stats.DiskChange[path])))
+	}
+
+	b.WriteString(i18n.T(lang, "digest.execs", stats.Execs))
+	b.WriteString(i18n.T(lang, "digest.tasks", stats.Tasks))
+	b.WriteString(i18n.T(lang, "digest.restarts", stats.Restarts))
+
+	if failed := stats.FailedChecks(); len(failed) > 0 {
+		parts := make([]string, len(failed))
+		for i, name := range failed {
+			parts[i] = fmt.Sprintf("%s ×%d", name, stats.Failed[name])
+		}
+		b.WriteString(i18n.T(lang, "digest.failed", strings.Join(parts, ", ")))
+	} else {
+		b.WriteString(i18n.T(lang, "digest.failed_none"))
+	}
+
+	sent, suppressed := 0, 0
+	for _, r := range d.watchdog.History(from) {
+		if !r.Time.Before(to) {
+			break
+		}
+		if r.Suppressed {
+			suppressed++
+		} else {
+			sent++
+		}
+	}
+	b.WriteString(i18n.T(lang, "digest.alerts", sent, suppressed))
+	return b.String()
+}
+
+// formatChange renders a signed size difference, e.g. "+1.2 GB"
+func formatChange(delta float64) string {
+	if delta < 0 {
+		return "-" + formatBytes(uint64(-delta))
+	}
+	return "+" + formatBytes(uint64(delta))
+}
+
+// deviceUptime returns the device uptime as reported by uptime -p
+func deviceUptime() string {
+	output, err := exec.Command("uptime", "-p").Output()
+	if err != nil {
+		return "N/A"
+	}
+	return strings.TrimSpace(string(output))
+}
@@ -0,0 +1,419 @@
+package system
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"android-server-brain/config"
+	"android-server-brain/internal/i18n"
+)
+
+const (
+	// diskHistory is how far back samples are kept for the growth prediction
+	diskHistory = 24 * time.Hour
+	// diskMinSpan is the minimum sample span before predicting anything
+	diskMinSpan = 30 * time.Minute
+	// diskHysteresis is the percent usage must drop below a threshold to recover
+	diskHysteresis = 2
+)
+
+// DiskSpace is the capacity of the filesystem holding a path
+type DiskSpace struct {
+	Total uint64
+	Free  uint64 // available to unprivileged users
+	Used  uint64
+}
+
+// UsedPercent returns the used share the way df reports it
+func (d DiskSpace) UsedPercent() float64 {
+	if d.Used+d.Free == 0 {
+		return 0
+	}
+	return float64(d.Used) / float64(d.Used+d.Free) * 100
+}
+
+// getDiskSpace reads filesystem statistics for a path
+func getDiskSpace(path string) (DiskSpace, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return DiskSpace{}, fmt.Errorf("failed to stat filesystem: %w", err)
+	}
+	bsize := uint64(st.Bsize)
+	total := uint64(st.Blocks) * bsize
+	return DiskSpace{
+		Total: total,
+		Free:  uint64(st.Bavail) * bsize,
+		Used:  total - uint64(st.Bfree)*bsize,
+	}, nil
+}
+
+// diskSample is one usage reading used for the growth prediction
+type diskSample struct {
+	at   time.Time
+	used uint64
+}
+
+// diskPathState is the alert state of a single monitored path
+type diskPathState struct {
+	path      string
+	level     CheckStatus
+	predicted bool // "filling up" alert already sent
+	samples   []diskSample
+}
+
+// DiskCheck watches free space on the data partition and the storage dir
+type DiskCheck struct {
+	config   config.DiskConfig
+	interval time.Duration
+	paths    []*diskPathState
+
+	// stat is the filesystem source, replaceable for tests
+	stat func(path string) (DiskSpace, error)
+}
+
+// NewDiskCheck creates the built-in disk space check
+func NewDiskCheck(cfg config.DiskConfig) *DiskCheck {
+	c := &DiskCheck{
+		config:   cfg,
+		interval: time.Duration(cfg.IntervalMinutes) * time.Minute,
+		stat:     getDiskSpace,
+	}
+	for _, path := range cfg.Paths {
+		c.paths = append(c.paths, &diskPathState{path: path, level: StatusOK})
+	}
+	return c
+}
+
+// Name implements Check
+func (c *DiskCheck) Name() string { return "disk" }
+
+// Interval implements Check
+func (c *DiskCheck) Interval() time.Duration { return c.interval }
+
+// Run implements Check
+func (c *DiskCheck) Run(ctx context.Context) CheckResult {
+	status := StatusOK
+	var alerts []Alert
+	var parts []Message
+	metrics := make(Metrics)
+
+	for _, st := range c.paths {
+		space, err := c.stat(st.path)
+		if err != nil {
+			parts = append(parts, Msg("check.disk_error", st.path, err))
+			if status < StatusWarning {
+				status = StatusUnknown
+			}
+			continue
+		}
+
+		alerts = append(alerts, c.checkPath(st, space, time.Now())...)
+		metrics[MetricDiskUsedPrefix+st.path] = float64(space.Used)
+		if st.level > status {
+			status = st.level
+		}
+
+		if eta, ok := st.timeToFull(space); ok {
+			parts = append(parts, Msg("check.disk_path_eta", st.path, space.UsedPercent(), formatBytes(space.Free), formatETA(eta)))
+		} else {
+			parts = append(parts, Msg("check.disk_path", st.path, space.UsedPercent(), formatBytes(space.Free)))
+		}
+	}
+
+	return CheckResult{
+		Status:  status,
+		Summary: Msg("check.disk_summary", parts),
+		Alerts:  alerts,
+		Metrics: metrics,
+	}
+}
+
+// diskCheckState is the persisted alert state of one DiskCheck path
+type diskCheckState struct {
+	Level     CheckStatus  `json:"level"`
+	Predicted bool         `json:"predicted"`
+	Samples   []diskRecord `json:"samples"`
+}
+
+type diskRecord struct {
+	At   time.Time `json:"at"`
+	Used uint64    `json:"used"`
+}
+
+// SaveState implements StatefulCheck
+func (c *DiskCheck) SaveState() interface{} {
+	state := make(map[string]diskCheckState, len(c.paths))
+	for _, st := range c.paths {
+		s := diskCheckState{Level: st.level, Predicted: st.predicted}
+		for _, sample := range st.samples {
+			s.Samples = append(s.Samples, diskRecord{At: sample.at, Used: sample.used})
+		}
+		state[st.path] = s
+	}
+	return state
+}
+
+// RestoreState implements StatefulCheck
+func (c *DiskCheck) RestoreState(data json.RawMessage) error {
+	var state map[string]diskCheckState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+	for _, st := range c.paths {
+		s, ok := state[st.path]
+		if !ok {
+			continue
+		}
+		st.level = s.Level
+		st.predicted = s.Predicted
+		st.samples = nil
+		for _, sample := range s.Samples {
+			st.samples = append(st.samples, diskSample{at: sample.At, used: sample.Used})
+		}
+	}
+	return nil
+}
+
+// checkPath records a sample and alerts on level changes and when the
+// growth trend predicts the filesystem will be full soon
+func (c *DiskCheck) checkPath(st *diskPathState, space DiskSpace, now time.Time) []Alert {
+	st.addSample(now, space.Used)
+
+	level := c.evaluate(st.level, space)
+	previous := st.level
+	st.level = level
+
+	var alerts []Alert
+	if level != previous {
+		key := "watchdog.disk_" + level.String()
+		alerts = append(alerts, Alert{
+			Severity: level,
+			Message:  Msg(key, st.path, space.UsedPercent(), formatBytes(space.Free), formatBytes(space.Total)),
+		})
+	}
+
+	eta, filling := st.timeToFull(space)
+	soon := filling && eta <= time.Duration(c.config.PredictHours)*time.Hour
+	if soon && !st.predicted {
+		alerts = append(alerts, Alert{
+			Severity: StatusWarning,
+			Message:  Msg("watchdog.disk_prediction", st.path, formatETA(eta), formatBytes(uint64(st.growthPerHour())), formatBytes(space.Free)),
+		})
+	}
+	st.predicted = soon
+	return alerts
+}
+
+// evaluate maps disk usage to a status using the percent and absolute
+// thresholds. A level is only left once usage drops a margin below its
+// threshold, so a disk hovering around a boundary doesn't cause flapping.
+func (c *DiskCheck) evaluate(current CheckStatus, space DiskSpace) CheckStatus {
+	level := diskLevel(c.config, space, 0)
+	if level >= current {
+		return level
+	}
+	if recovered := diskLevel(c.config, space, diskHysteresis); recovered < current {
+		return recovered
+	}
+	return current
+}
+
+// diskLevel applies the thresholds lowered by margin percent
+func diskLevel(t config.DiskConfig, space DiskSpace, margin float64) CheckStatus {
+	used := space.UsedPercent() + margin
+	minFree := uint64(t.MinFreeMB) * 1024 * 1024
+	switch {
+	case used >= t.CriticalPercent || space.Free < minFree:
+		return StatusCritical
+	case used >= t.WarningPercent:
+		return StatusWarning
+	default:
+		return StatusOK
+	}
+}
+
+// addSample appends a reading and drops those older than the history window
+func (st *diskPathState) addSample(at time.Time, used uint64) {
+	st.samples = append(st.samples, diskSample{at: at, used: used})
+
+	cutoff := at.Add(-diskHistory)
+	for len(st.samples) > 0 && st.samples[0].at.Before(cutoff) {
+		st.samples = st.samples[1:]
+	}
+}
+
+// growthPerHour estimates the usage growth in bytes per hour with a
+// least-squares fit over the kept samples
+func (st *diskPathState) growthPerHour() float64 {
+	n := len(st.samples)
+	if n < 3 || st.samples[n-1].at.Sub(st.samples[0].at) < diskMinSpan {
+		return 0
+	}
+
+	origin := st.samples[0].at
+	var sumX, sumY, sumXY, sumXX float64
+	for _, s := range st.samples {
+		x := s.at.Sub(origin).Hours()
+		y := float64(s.used)
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+
+	denominator := float64(n)*sumXX - sumX*sumX
+	if denominator == 0 {
+		return 0
+	}
+	return (float64(n)*sumXY - sumX*sumY) / denominator
+}
+
+// timeToFull predicts when the filesystem fills up at the current growth rate
+func (st *diskPathState) timeToFull(space DiskSpace) (time.Duration, bool) {
+	rate := st.growthPerHour()
+	if rate <= 0 {
+		return 0, false
+	}
+	hours := float64(space.Free) / rate
+	return time.Duration(hours * float64(time.Hour)), true
+}
+
+// formatETA renders a prediction as "in ~N hours" or "in ~N days"
+func formatETA(d time.Duration) Message {
+	hours := int(d.Hours() + 0.5)
+	if hours < 48 {
+		if hours < 1 {
+			hours = 1
+		}
+		return Msg("disk.eta_hours", hours)
+	}
+	return Msg("disk.eta_days", (hours+12)/24)
+}
+
+// formatBytes renders a size with a binary unit, e.g. "3.2 GB"
+func formatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// DirUsage is the size of one entry below a directory
+type DirUsage struct {
+	Name  string
+	Size  uint64
+	IsDir bool
+}
+
+// resolveInside resolves rel below root and rejects anything, including
+// symlinks, that points outside of it
+func resolveInside(root, rel string) (string, error) {
+	realRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return "", err
+	}
+
+	target, err := filepath.EvalSymlinks(filepath.Join(realRoot, filepath.Clean("/"+rel)))
+	if err != nil {
+		return "", err
+	}
+
+	inside, err := filepath.Rel(realRoot, target)
+	if err != nil || inside == ".." || strings.HasPrefix(inside, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("path is outside of %s", root)
+	}
+	return target, nil
+}
+
+// DiskUsage returns the entries of dir ordered by size, largest first
+func DiskUsage(ctx context.Context, dir string) ([]DirUsage, uint64, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read directory: %w", err)
+	}
+
+	var usage []DirUsage
+	var total uint64
+	for _, entry := range entries {
+		size, err := treeSize(ctx, filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, 0, err
+		}
+		usage = append(usage, DirUsage{Name: entry.Name(), Size: size, IsDir: entry.IsDir()})
+		total += size
+	}
+
+	sort.Slice(usage, func(i, j int) bool { return usage[i].Size > usage[j].Size })
+	return usage, total, nil
+}
+
+// treeSize sums the file sizes below path without following symlinks
+func treeSize(ctx context.Context, path string) (uint64, error) {
+	var size uint64
+	err := filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// Skip unreadable entries instead of failing the whole scan
+			return nil
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if d.Type().IsRegular() {
+			if info, err := d.Info(); err == nil {
+				size += uint64(info.Size())
+			}
+		}
+		return nil
+	})
+	return size, err
+}
+
+// GetDiskUsage returns a formatted report of the largest entries below a
+// path relative to the storage root
+func GetDiskUsage(lang i18n.Lang, root, rel string, limit int) string {
+	dir, err := resolveInside(root, rel)
+	if err != nil {
+		return i18n.T(lang, "du.outside", rel, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	usage, total, err := DiskUsage(ctx, dir)
+	if err != nil {
+		return i18n.T(lang, "du.failed", rel, err)
+	}
+
+	shown := filepath.ToSlash(filepath.Clean("/" + rel))
+	var b strings.Builder
+	b.WriteString(i18n.T(lang, "du.title", shown, formatBytes(total)))
+	if len(usage) == 0 {
+		b.WriteString(i18n.T(lang, "du.empty"))
+	}
+	for i, entry := range usage {
+		if i == limit {
+			b.WriteString(i18n.T(lang, "du.more", len(usage)-limit))
+			break
+		}
+		name := entry.Name
+		if entry.IsDir {
+			name += "/"
+		}
+		b.WriteString(i18n.T(lang, "du.line", formatBytes(entry.Size), name))
+	}
+	return b.String()
+}
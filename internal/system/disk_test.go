@@ -0,0 +1,38 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"android-server-brain/internal/i18n"
+)
+
+// codeSpans matches Markdown code spans, inside which Telegram doesn't
+// parse entities
+var codeSpans = regexp.MustCompile("`[^`]*`")
+
+func TestDiskUsageEscapesNames(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "asb_files", "node_modules"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "asb_files", "my_notes.txt"), []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, lang := range []i18n.Lang{i18n.English, i18n.Russian} {
+		text := GetDiskUsage(lang, root, "asb_files", 10)
+		for _, name := range []string{"`node_modules/`", "`my_notes.txt`"} {
+			if !strings.Contains(text, name) {
+				t.Errorf("%s: %s missing from %q", lang, name, text)
+			}
+		}
+		// An underscore outside a code span would start italics
+		if rest := codeSpans.ReplaceAllString(text, ""); strings.Contains(rest, "_") {
+			t.Errorf("%s: unescaped underscore in %q", lang, text)
+		}
+	}
+}
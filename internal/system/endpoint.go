@@ -0,0 +1,175 @@
+package system
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"android-server-brain/config"
+)
+
+// maxProbeBody limits how much of a response is read for body matching
+const maxProbeBody = 1 << 20
+
+// EndpointCheck probes an HTTP(S) URL or a TCP address and alerts when its
+// state changes
+type EndpointCheck struct {
+	config config.EndpointConfig
+	client *http.Client
+	last   CheckStatus
+}
+
+// NewEndpointCheck creates a check for a configured endpoint
+func NewEndpointCheck(cfg config.EndpointConfig) *EndpointCheck {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: cfg.InsecureSkipVerify}
+
+	return &EndpointCheck{
+		config: cfg,
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   time.Duration(cfg.TimeoutSeconds) * time.Second,
+		},
+	}
+}
+
+// Name implements Check
+func (c *EndpointCheck) Name() string { return c.config.Name }
+
+// Interval implements Check
+func (c *EndpointCheck) Interval() time.Duration {
+	return time.Duration(c.config.IntervalSeconds) * time.Second
+}
+
+// target returns the probed URL or address
+func (c *EndpointCheck) target() string {
+	if c.config.URL != "" {
+		return c.config.URL
+	}
+	return c.config.Address
+}
+
+// Run implements Check
+func (c *EndpointCheck) Run(ctx context.Context) CheckResult {
+	var status CheckStatus
+	var summary Message
+	if c.config.URL != "" {
+		status, summary = c.probeHTTP(ctx)
+	} else {
+		status, summary = c.probeTCP(ctx)
+	}
+
+	previous := c.last
+	c.last = status
+
+	// Alert on state changes only; a healthy first run is not news
+	var alerts []Alert
+	if status != previous && !(previous == StatusUnknown && status == StatusOK) {
+		alerts = append(alerts, Alert{
+			Severity: status,
+			Message:  Msg("watchdog.endpoint_"+status.String(), c.config.Name, c.target(), summary),
+		})
+	}
+
+	return CheckResult{
+		Status:  status,
+		Summary: summary,
+		Alerts:  alerts,
+	}
+}
+
+// endpointCheckState is the persisted alert state of EndpointCheck
+type endpointCheckState struct {
+	Last CheckStatus `json:"last"`
+}
+
+// SaveState implements StatefulCheck
+func (c *EndpointCheck) SaveState() interface{} {
+	return endpointCheckState{Last: c.last}
+}
+
+// RestoreState implements StatefulCheck
+func (c *EndpointCheck) RestoreState(data json.RawMessage) error {
+	var s endpointCheckState
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	c.last = s.Last
+	return nil
+}
+
+// probeHTTP checks status code, body, latency and certificate expiry
+func (c *EndpointCheck) probeHTTP(ctx context.Context) (CheckStatus, Message) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.config.URL, nil)
+	if err != nil {
+		return StatusCritical, Msg("check.endpoint_error", err)
+	}
+
+	started := time.Now()
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return StatusCritical, Msg("check.endpoint_error", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxProbeBody))
+	latency := time.Since(started)
+	if err != nil {
+		return StatusCritical, Msg("check.endpoint_error", err)
+	}
+
+	if expected := c.config.ExpectStatus; expected != 0 && resp.StatusCode != expected {
+		return StatusCritical, Msg("check.endpoint_status_expected", resp.StatusCode, expected)
+	} else if expected == 0 && (resp.StatusCode < 200 || resp.StatusCode >= 400) {
+		return StatusCritical, Msg("check.endpoint_status", resp.StatusCode)
+	}
+
+	if c.config.BodyContains != "" && !strings.Contains(string(body), c.config.BodyContains) {
+		return StatusCritical, Msg("check.endpoint_body", c.config.BodyContains)
+	}
+
+	status, summary := c.checkLatency(latency, Msg("check.endpoint_http_ok", resp.StatusCode, latency.Milliseconds()))
+	if resp.TLS == nil || len(resp.TLS.PeerCertificates) == 0 {
+		return status, summary
+	}
+
+	// The leaf certificate decides when the endpoint breaks
+	remaining := time.Until(resp.TLS.PeerCertificates[0].NotAfter)
+	days := int(remaining.Hours() / 24)
+	switch {
+	case remaining <= 0:
+		return StatusCritical, Msg("check.endpoint_tls_expired")
+	case days < c.config.TLSExpiryDays:
+		return StatusWarning, Msg("check.endpoint_tls_expiring", days)
+	}
+	return status, Msg("check.endpoint_tls", summary, days)
+}
+
+// probeTCP checks that the address accepts connections in time
+func (c *EndpointCheck) probeTCP(ctx context.Context) (CheckStatus, Message) {
+	dialer := net.Dialer{Timeout: time.Duration(c.config.TimeoutSeconds) * time.Second}
+
+	started := time.Now()
+	conn, err := dialer.DialContext(ctx, "tcp", c.config.Address)
+	if err != nil {
+		return StatusCritical, Msg("check.endpoint_error", err)
+	}
+	latency := time.Since(started)
+	conn.Close()
+
+	return c.checkLatency(latency, Msg("check.endpoint_tcp_ok", latency.Milliseconds()))
+}
+
+// checkLatency downgrades a healthy result to a warning when it was slow
+func (c *EndpointCheck) checkLatency(latency time.Duration, ok Message) (CheckStatus, Message) {
+	limit := time.Duration(c.config.MaxLatencyMs) * time.Millisecond
+	if latency > limit {
+		return StatusWarning, Msg("check.endpoint_slow", latency.Milliseconds(), limit.Milliseconds())
+	}
+	return StatusOK, ok
+}
@@ -0,0 +1,188 @@
+package system
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"android-server-brain/config"
+)
+
+// endpointConfig returns an endpoint with the defaults of config.json applied
+func endpointConfig(url string) config.EndpointConfig {
+	return config.EndpointConfig{
+		Name:               "site",
+		URL:                url,
+		MaxLatencyMs:       2000,
+		TLSExpiryDays:      14,
+		TimeoutSeconds:     5,
+		IntervalSeconds:    60,
+		InsecureSkipVerify: true, // httptest certificates are self-signed
+	}
+}
+
+func TestEndpointHTTP(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/down":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case "/slow":
+			time.Sleep(50 * time.Millisecond)
+		case "/created":
+			w.WriteHeader(http.StatusCreated)
+		case "/moved":
+			http.Redirect(w, r, "/", http.StatusFound)
+			return
+		case "/not-modified":
+			w.WriteHeader(http.StatusNotModified)
+			return
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		}
+		w.Write([]byte("hello world"))
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name    string
+		path    string
+		modify  func(*config.EndpointConfig)
+		status  CheckStatus
+		summary string
+	}{
+		{"healthy", "/", nil, StatusOK, "check.endpoint_http_ok"},
+		{"other 2xx", "/created", nil, StatusOK, "check.endpoint_http_ok"},
+		{"redirect followed", "/moved", nil, StatusOK, "check.endpoint_http_ok"},
+		{"3xx", "/not-modified", nil, StatusOK, "check.endpoint_http_ok"},
+		{"client error", "/missing", nil, StatusCritical, "check.endpoint_status"},
+		{"server error", "/down", nil, StatusCritical, "check.endpoint_status"},
+		{"unexpected status", "/", func(c *config.EndpointConfig) { c.ExpectStatus = http.StatusNoContent }, StatusCritical, "check.endpoint_status_expected"},
+		{"expected error status", "/down", func(c *config.EndpointConfig) { c.ExpectStatus = http.StatusServiceUnavailable }, StatusOK, "check.endpoint_http_ok"},
+		{"body matches", "/", func(c *config.EndpointConfig) { c.BodyContains = "world" }, StatusOK, "check.endpoint_http_ok"},
+		{"body differs", "/", func(c *config.EndpointConfig) { c.BodyContains = "maintenance" }, StatusCritical, "check.endpoint_body"},
+		{"slow", "/slow", func(c *config.EndpointConfig) { c.MaxLatencyMs = 10 }, StatusWarning, "check.endpoint_slow"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := endpointConfig(srv.URL + tt.path)
+			if tt.modify != nil {
+				tt.modify(&cfg)
+			}
+			result := NewEndpointCheck(cfg).Run(context.Background())
+			if result.Status != tt.status || result.Summary.Key != tt.summary {
+				t.Errorf("got %s %s, want %s %s", result.Status, result.Summary.Key, tt.status, tt.summary)
+			}
+		})
+	}
+}
+
+func TestEndpointTLSExpiry(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	// The httptest certificate is valid for decades
+	cfg := endpointConfig(srv.URL)
+	if result := NewEndpointCheck(cfg).Run(context.Background()); result.Status != StatusOK || result.Summary.Key != "check.endpoint_tls" {
+		t.Errorf("valid certificate: got %s %s", result.Status, result.Summary.Key)
+	}
+
+	cfg.TLSExpiryDays = 100 * 365
+	if result := NewEndpointCheck(cfg).Run(context.Background()); result.Status != StatusWarning || result.Summary.Key != "check.endpoint_tls_expiring" {
+		t.Errorf("expiring certificate: got %s %s", result.Status, result.Summary.Key)
+	}
+
+	expired := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	expired.TLS = &tls.Config{Certificates: []tls.Certificate{expiredCertificate(t)}}
+	expired.StartTLS()
+	defer expired.Close()
+
+	if result := NewEndpointCheck(endpointConfig(expired.URL)).Run(context.Background()); result.Status != StatusCritical || result.Summary.Key != "check.endpoint_tls_expired" {
+		t.Errorf("expired certificate: got %s %s", result.Status, result.Summary.Key)
+	}
+}
+
+// expiredCertificate creates a self-signed certificate for 127.0.0.1 that
+// expired yesterday
+func expiredCertificate(t *testing.T) tls.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "expired"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-48 * time.Hour),
+		NotAfter:     time.Now().Add(-24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+func TestEndpointAlertsOnStateChanges(t *testing.T) {
+	var down atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if down.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer srv.Close()
+
+	c := NewEndpointCheck(endpointConfig(srv.URL))
+	steps := []struct {
+		down  bool
+		alert string // expected alert key, empty for none
+	}{
+		{false, ""}, // a healthy first run is not news
+		{false, ""},
+		{true, "watchdog.endpoint_critical"},
+		{true, ""},
+		{false, "watchdog.endpoint_ok"},
+		{false, ""},
+	}
+	for i, step := range steps {
+		down.Store(step.down)
+		alerts := c.Run(context.Background()).Alerts
+		switch {
+		case step.alert == "" && len(alerts) != 0:
+			t.Errorf("run %d: unexpected alert %s", i, alerts[0].Message.Key)
+		case step.alert != "" && (len(alerts) != 1 || alerts[0].Message.Key != step.alert):
+			t.Errorf("run %d: got %v, want %s", i, alerts, step.alert)
+		}
+	}
+}
+
+func TestEndpointTCP(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := endpointConfig("")
+	cfg.Address = l.Addr().String()
+
+	if result := NewEndpointCheck(cfg).Run(context.Background()); result.Status != StatusOK {
+		t.Errorf("listening: got %s %s", result.Status, result.Summary.Key)
+	}
+	l.Close()
+	if result := NewEndpointCheck(cfg).Run(context.Background()); result.Status != StatusCritical {
+		t.Errorf("closed: got %s %s", result.Status, result.Summary.Key)
+	}
+}
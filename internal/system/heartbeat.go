@@ -0,0 +1,180 @@
+package system
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"android-server-brain/config"
+	"android-server-brain/internal/i18n"
+)
+
+// maxPingBody is the largest body healthchecks-style services accept
+const maxPingBody = 100 * 1024
+
+// Ping signals appended to a ping URL
+const (
+	SignalSuccess = ""
+	SignalStart   = "start"
+	SignalFail    = "fail"
+)
+
+// heartbeatPayload is the status body sent with every heartbeat
+type heartbeatPayload struct {
+	Time   time.Time                 `json:"time"`
+	Uptime string                    `json:"uptime"`
+	Paused bool                      `json:"paused"`
+	Checks map[string]heartbeatCheck `json:"checks"`
+}
+
+type heartbeatCheck struct {
+	Status  string    `json:"status"`
+	Summary string    `json:"summary"`
+	LastRun time.Time `json:"last_run"`
+}
+
+// Heartbeat pings an external monitor periodically and reports the start,
+// success or failure of scheduled jobs
+type Heartbeat struct {
+	config   config.HeartbeatConfig
+	watchdog *Watchdog
+	client   *http.Client
+	interval time.Duration
+
+	mu     sync.Mutex
+	failed bool // last ping failed, to log only state changes
+}
+
+// NewHeartbeat creates the heartbeat for the watchdog's status
+func NewHeartbeat(cfg config.HeartbeatConfig, watchdog *Watchdog) *Heartbeat {
+	return &Heartbeat{
+		config:   cfg,
+		watchdog: watchdog,
+		client:   &http.Client{Timeout: time.Duration(cfg.TimeoutSeconds) * time.Second},
+		interval: time.Duration(cfg.IntervalMinutes) * time.Minute,
+	}
+}
+
+// Start pings right away and then every interval until ctx is cancelled
+func (h *Heartbeat) Start(ctx context.Context) {
+	if h.config.URL == "" {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(h.interval)
+		defer ticker.Stop()
+
+		for {
+			h.Beat(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	log.Printf("Heartbeat enabled every %d minutes", h.config.IntervalMinutes)
+}
+
+// Beat sends one heartbeat with the current check statuses
+func (h *Heartbeat) Beat(ctx context.Context) error {
+	payload := heartbeatPayload{
+		Time:   time.Now(),
+		Uptime: time.Since(h.watchdog.StartTime()).Round(time.Second).String(),
+		Paused: h.watchdog.Paused(),
+		Checks: make(map[string]heartbeatCheck),
+	}
+
+	signal := SignalSuccess
+	for _, info := range h.watchdog.Checks() {
+		payload.Checks[info.Name] = heartbeatCheck{
+			Status:  info.Status.String(),
+			Summary: info.Summary.Render(i18n.Default),
+			LastRun: info.LastRun,
+		}
+		if h.config.FailOnCritical && info.Status == StatusCritical {
+			signal = SignalFail
+		}
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode heartbeat: %w", err)
+	}
+
+	err = h.ping(ctx, h.config.URL, signal, body)
+	h.mu.Lock()
+	switch {
+	case err != nil && !h.failed:
+		log.Printf("Heartbeat failed: %v", err)
+	case err == nil && h.failed:
+		log.Printf("Heartbeat recovered")
+	}
+	h.failed = err != nil
+	h.mu.Unlock()
+	return err
+}
+
+// JobStarted signals the start of a scheduled job that has a ping URL
+func (h *Heartbeat) JobStarted(job string) {
+	h.jobPing(job, SignalStart, "")
+}
+
+// JobFinished signals success or failure of a scheduled job with its output
+func (h *Heartbeat) JobFinished(job string, err error, output string) {
+	signal := SignalSuccess
+	if err != nil {
+		signal = SignalFail
+		output = strings.TrimSpace(strings.TrimSpace(output) + "\n" + err.Error())
+	}
+	h.jobPing(job, signal, output)
+}
+
+// jobPing sends a signal for a job and logs failures; jobs without a ping URL are skipped
+func (h *Heartbeat) jobPing(job, signal, output string) {
+	url, ok := h.config.Jobs[job]
+	if !ok {
+		return
+	}
+
+	// Keep the tail, which usually holds the error
+	if len(output) > maxPingBody {
+		output = output[len(output)-maxPingBody:]
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(h.config.TimeoutSeconds)*time.Second)
+	defer cancel()
+	if err := h.ping(ctx, url, signal, []byte(output)); err != nil {
+		log.Printf("Failed to ping job %s (%s): %v", job, signal, err)
+	}
+}
+
+// ping posts body to url, with the signal appended as a path segment
+func (h *Heartbeat) ping(ctx context.Context, url, signal string, body []byte) error {
+	if signal != SignalSuccess {
+		url = strings.TrimRight(url, "/") + "/" + signal
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to ping: %w", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("ping returned %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"android-server-brain/config"
+	"android-server-brain/internal/i18n"
+	"android-server-brain/internal/transport/memory"
+)
+
+// ping is a request received by the test monitor
+type ping struct {
+	path string
+	body string
+}
+
+// newMonitor starts a server that records every ping
+func newMonitor(t *testing.T) (*httptest.Server, <-chan ping) {
+	t.Helper()
+	pings := make(chan ping, 100)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		pings <- ping{r.URL.Path, string(body)}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, pings
+}
+
+// nextPing waits for the next ping the monitor received
+func nextPing(t *testing.T, pings <-chan ping) ping {
+	t.Helper()
+	select {
+	case p := <-pings:
+		return p
+	case <-time.After(2 * time.Second):
+		t.Fatal("no ping received")
+		return ping{}
+	}
+}
+
+// statusCheck reports a fixed status
+type statusCheck struct {
+	status CheckStatus
+}
+
+func (c *statusCheck) Name() string            { return "fake" }
+func (c *statusCheck) Interval() time.Duration { return time.Hour }
+func (c *statusCheck) Run(ctx context.Context) CheckResult {
+	return CheckResult{Status: c.status, Summary: Msg("check.restored")}
+}
+
+func newTestHeartbeat(t *testing.T, hc config.HeartbeatConfig) (*Heartbeat, *Watchdog) {
+	t.Helper()
+	cfg := &config.Config{StorageDir: t.TempDir(), Heartbeat: hc}
+	watchdog := NewWatchdog(memory.New(), cfg, i18n.NewStore(""), time.Minute)
+	return NewHeartbeat(cfg.Heartbeat, watchdog), watchdog
+}
+
+func TestHeartbeatBeatsPeriodically(t *testing.T) {
+	srv, pings := newMonitor(t)
+	h, _ := newTestHeartbeat(t, config.HeartbeatConfig{URL: srv.URL + "/ping/abc", IntervalMinutes: 5, TimeoutSeconds: 5})
+	h.interval = 20 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	h.Start(ctx)
+
+	for i := 0; i < 3; i++ {
+		p := nextPing(t, pings)
+		if p.path != "/ping/abc" {
+			t.Errorf("beat %d: got path %s", i, p.path)
+		}
+		if !strings.Contains(p.body, `"checks"`) || !strings.Contains(p.body, `"uptime"`) {
+			t.Errorf("beat %d: got body %s", i, p.body)
+		}
+	}
+}
+
+func TestHeartbeatFailOnCritical(t *testing.T) {
+	srv, pings := newMonitor(t)
+	h, watchdog := newTestHeartbeat(t, config.HeartbeatConfig{URL: srv.URL + "/ping/abc", TimeoutSeconds: 5, FailOnCritical: true})
+	check := &statusCheck{status: StatusWarning}
+	watchdog.AddCheck(check)
+
+	steps := []struct {
+		status CheckStatus
+		path   string
+	}{
+		{StatusWarning, "/ping/abc"},
+		{StatusCritical, "/ping/abc/fail"},
+		{StatusOK, "/ping/abc"},
+	}
+	for _, step := range steps {
+		check.status = step.status
+		watchdog.RunNow(check.Name())
+		if err := h.Beat(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if p := nextPing(t, pings); p.path != step.path {
+			t.Errorf("%s: got %s, want %s", step.status, p.path, step.path)
+		}
+	}
+
+	// Without fail_on_critical the status is only reported in the body
+	h.config.FailOnCritical = false
+	check.status = StatusCritical
+	watchdog.RunNow(check.Name())
+	h.Beat(context.Background())
+	if p := nextPing(t, pings); p.path != "/ping/abc" {
+		t.Errorf("got %s, want a success ping", p.path)
+	}
+}
+
+func TestHeartbeatJobSignals(t *testing.T) {
+	srv, pings := newMonitor(t)
+	h, _ := newTestHeartbeat(t, config.HeartbeatConfig{
+		TimeoutSeconds: 5,
+		Jobs:           map[string]string{"backup": srv.URL + "/ping/backup/"},
+	})
+
+	h.JobStarted("backup")
+	if p := nextPing(t, pings); p.path != "/ping/backup/start" {
+		t.Errorf("start: got %s", p.path)
+	}
+
+	h.JobFinished("backup", nil, "42 files")
+	if p := nextPing(t, pings); p.path != "/ping/backup/" || p.body != "42 files" {
+		t.Errorf("success: got %+v", p)
+	}
+
+	h.JobFinished("backup", errors.New("exit status 1"), "disk full\n")
+	if p := nextPing(t, pings); p.path != "/ping/backup/fail" || p.body != "disk full\nexit status 1" {
+		t.Errorf("failure: got %+v", p)
+	}
+
+	// Jobs without a ping URL are not reported
+	h.JobStarted("other")
+	select {
+	case p := <-pings:
+		t.Errorf("unexpected ping %+v", p)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -1,17 +1,18 @@
 package system
 
 import (
-	"fmt"
 	"os/exec"
 	"strings"
+
+	"android-server-brain/internal/i18n"
 )
 
 // GetSystemStatus collects data from Termux and OS
-func GetSystemStatus() string {
+func GetSystemStatus(lang i18n.Lang) string {
 	// Battery info from termux-api
 	battery, err := exec.Command("termux-battery-status").Output()
 	if err != nil {
-		battery = []byte("Unavailable (API missing)")
+		battery = []byte(i18n.T(lang, "status.battery_unavailable"))
 	}
 
 	// Storage info for the data partition
@@ -26,11 +27,7 @@ func GetSystemStatus() string {
 		uptime = []byte("N/A")
 	}
 
-	return fmt.Sprintf(
-		"📊 *System Status*\n\n"+
-			"🔋 *Battery:* %s\n"+
-			"💾 *Free Space:* %s\n"+
-			"⏱ *Uptime:* %s",
+	return i18n.T(lang, "status.report",
 		strings.TrimSpace(string(battery)),
 		strings.TrimSpace(string(df)),
 		strings.TrimSpace(string(uptime)),
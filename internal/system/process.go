@@ -0,0 +1,286 @@
+package system
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"android-server-brain/config"
+)
+
+const (
+	// processStableAfter is how long a process must stay up to reset the backoff
+	processStableAfter = 10 * time.Minute
+	// processMaxBackoff caps the delay between restart attempts
+	processMaxBackoff = 30 * time.Minute
+)
+
+// ProcessCheck alerts when a watched process disappears and optionally
+// restarts it with exponential backoff and an hourly restart limit
+type ProcessCheck struct {
+	config config.ProcessConfig
+
+	// probe and start are the process sources, replaceable for tests
+	probe func(ctx context.Context) (string, bool, error)
+	start func(command string) (int, error)
+
+	down          bool
+	upSince       time.Time
+	backoff       time.Duration
+	nextRestart   time.Time
+	restarts      []time.Time // restarts within the last hour
+	limitNotified bool
+}
+
+// NewProcessCheck creates a liveness check for a configured process
+func NewProcessCheck(cfg config.ProcessConfig) *ProcessCheck {
+	c := &ProcessCheck{
+		config: cfg,
+		start:  startDetached,
+	}
+	c.probe = c.findProcess
+	return c
+}
+
+// Name implements Check
+func (c *ProcessCheck) Name() string { return c.config.Name }
+
+// Interval implements Check
+func (c *ProcessCheck) Interval() time.Duration {
+	return time.Duration(c.config.IntervalSeconds) * time.Second
+}
+
+// Run implements Check
+func (c *ProcessCheck) Run(ctx context.Context) CheckResult {
+	pids, alive, err := c.probe(ctx)
+	if err != nil {
+		return CheckResult{
+			Status:  StatusUnknown,
+			Summary: Msg("check.error", err),
+		}
+	}
+
+	now := time.Now()
+	if alive {
+		return c.running(now, pids)
+	}
+
+	var alerts []Alert
+	if !c.down {
+		c.down = true
+		c.upSince = time.Time{}
+		key := "watchdog.process_down"
+		if c.config.AutoRestart {
+			key = "watchdog.process_down_restarting"
+		}
+		alerts = append(alerts, Alert{
+			Severity: StatusCritical,
+			Message:  Msg(key, c.config.Name),
+		})
+	}
+
+	restarted := 0.0
+	if c.config.AutoRestart {
+		alerts = append(alerts, c.restart(now)...)
+		if n := len(c.restarts); n > 0 && c.restarts[n-1].Equal(now) {
+			restarted = 1
+		}
+	}
+
+	return CheckResult{
+		Status:  StatusCritical,
+		Summary: Msg("check.process_down"),
+		Alerts:  alerts,
+		Metrics: Metrics{MetricRestarts: restarted},
+	}
+}
+
+// processCheckState is the persisted alert and restart state of ProcessCheck
+type processCheckState struct {
+	Down          bool          `json:"down"`
+	UpSince       time.Time     `json:"up_since"`
+	Backoff       time.Duration `json:"backoff"`
+	NextRestart   time.Time     `json:"next_restart"`
+	Restarts      []time.Time   `json:"restarts"`
+	LimitNotified bool          `json:"limit_notified"`
+}
+
+// SaveState implements StatefulCheck
+func (c *ProcessCheck) SaveState() interface{} {
+	return processCheckState{
+		Down:          c.down,
+		UpSince:       c.upSince,
+		Backoff:       c.backoff,
+		NextRestart:   c.nextRestart,
+		Restarts:      c.restarts,
+		LimitNotified: c.limitNotified,
+	}
+}
+
+// RestoreState implements StatefulCheck
+func (c *ProcessCheck) RestoreState(data json.RawMessage) error {
+	var s processCheckState
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	c.down = s.Down
+	c.upSince = s.UpSince
+	c.backoff = s.Backoff
+	c.nextRestart = s.NextRestart
+	c.restarts = s.Restarts
+	c.limitNotified = s.LimitNotified
+	return nil
+}
+
+// running handles a successful probe: reports recovery and resets the
+// backoff once the process has been stable for a while
+func (c *ProcessCheck) running(now time.Time, pids string) CheckResult {
+	var alerts []Alert
+	if c.down {
+		c.down = false
+		alerts = append(alerts, Alert{
+			Severity: StatusOK,
+			Message:  Msg("watchdog.process_up", c.config.Name, pids),
+		})
+	}
+
+	if c.upSince.IsZero() {
+		c.upSince = now
+	}
+	if now.Sub(c.upSince) >= processStableAfter {
+		c.backoff = 0
+		c.limitNotified = false
+	}
+
+	return CheckResult{
+		Status:  StatusOK,
+		Summary: Msg("check.process_running", pids),
+		Alerts:  alerts,
+	}
+}
+
+// restart starts the process again unless it is backing off or has hit
+// the hourly restart limit
+func (c *ProcessCheck) restart(now time.Time) []Alert {
+	if now.Before(c.nextRestart) {
+		return nil
+	}
+
+	cutoff := now.Add(-time.Hour)
+	for len(c.restarts) > 0 && c.restarts[0].Before(cutoff) {
+		c.restarts = c.restarts[1:]
+	}
+	if len(c.restarts) >= c.config.MaxRestartsPerHour {
+		if c.limitNotified {
+			return nil
+		}
+		c.limitNotified = true
+		return []Alert{{
+			Severity: StatusCritical,
+			Message:  Msg("watchdog.process_restart_limit", c.config.Name, c.config.MaxRestartsPerHour),
+		}}
+	}
+
+	c.limitNotified = false
+
+	// Exponential backoff between attempts
+	if c.backoff == 0 {
+		c.backoff = time.Duration(c.config.BackoffSeconds) * time.Second
+	} else if c.backoff *= 2; c.backoff > processMaxBackoff {
+		c.backoff = processMaxBackoff
+	}
+	c.nextRestart = now.Add(c.backoff)
+	c.restarts = append(c.restarts, now)
+	c.upSince = time.Time{}
+
+	pid, err := c.start(c.config.Command)
+	if err != nil {
+		log.Printf("Failed to restart process %s: %v", c.config.Name, err)
+		return []Alert{{
+			Severity: StatusCritical,
+			Message:  Msg("watchdog.process_restart_failed", c.config.Name, err),
+		}}
+	}
+
+	log.Printf("Restarted process %s (pid %d)", c.config.Name, pid)
+	return []Alert{{
+		Severity: StatusWarning,
+		Message:  Msg("watchdog.process_restarted", c.config.Name, pid, len(c.restarts), c.config.MaxRestartsPerHour, c.backoff),
+	}}
+}
+
+// findProcess looks the process up by pidfile, pattern or command line and
+// returns the matching pids
+func (c *ProcessCheck) findProcess(ctx context.Context) (string, bool, error) {
+	if c.config.Pidfile != "" {
+		return pidfileAlive(c.config.Pidfile)
+	}
+
+	pattern := c.config.Pattern
+	if pattern == "" {
+		pattern = c.config.Command
+	}
+	return pgrep(ctx, pattern)
+}
+
+// pidfileAlive reports whether the pid stored in a pidfile is running
+func pidfileAlive(path string) (string, bool, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("failed to read pidfile: %w", err)
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || pid <= 0 {
+		return "", false, fmt.Errorf("invalid pidfile %s: %q", path, strings.TrimSpace(string(data)))
+	}
+
+	// Signal 0 only checks existence; EPERM means it runs as another user
+	err = syscall.Kill(pid, 0)
+	if err == nil || errors.Is(err, syscall.EPERM) {
+		return strconv.Itoa(pid), true, nil
+	}
+	return "", false, nil
+}
+
+// pgrep finds processes whose full command line matches pattern
+func pgrep(ctx context.Context, pattern string) (string, bool, error) {
+	output, err := exec.CommandContext(ctx, "pgrep", "-f", pattern).Output()
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		// Exit code 1: no process matched
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("failed to run pgrep: %w", err)
+	}
+
+	pids := strings.Fields(string(output))
+	return strings.Join(pids, ", "), len(pids) > 0, nil
+}
+
+// startDetached starts a command in its own session so it outlives ASB
+func startDetached(command string) (int, error) {
+	cmd := exec.Command("sh", "-c", command)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+
+	if err := cmd.Start(); err != nil {
+		return 0, fmt.Errorf("failed to start command: %w", err)
+	}
+
+	// Reap the child when it exits
+	go cmd.Wait()
+	return cmd.Process.Pid, nil
+}
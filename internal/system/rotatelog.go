@@ -0,0 +1,110 @@
+package system
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// rotatingLog is an append-only log file that is rotated to path.1 ..
+// path.N once it grows past maxBytes
+type rotatingLog struct {
+	path     string
+	maxBytes int64
+	backups  int
+
+	mu           sync.Mutex
+	file         *os.File
+	size         int64
+	rotateFailed bool // to log only the first of repeated failures
+	writeFailed  bool
+}
+
+// openRotatingLog opens path for appending, creating its directory
+func openRotatingLog(path string, maxBytes int64, backups int) (*rotatingLog, error) {
+	l := &rotatingLog{path: path, maxBytes: maxBytes, backups: backups}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *rotatingLog) open() error {
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open log: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("failed to stat log: %w", err)
+	}
+	l.file, l.size = f, info.Size()
+	return nil
+}
+
+// Write appends p, rotating first if it would exceed the size limit. It
+// never fails while open: the output pipe of a program must keep draining,
+// so failures are logged once and the bytes that can't be written dropped.
+func (l *rotatingLog) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return 0, os.ErrClosed
+	}
+	if l.size > 0 && l.size+int64(len(p)) > l.maxBytes {
+		err := l.rotate()
+		if err != nil && !l.rotateFailed {
+			log.Printf("Failed to rotate %s, writing on: %v", l.path, err)
+		}
+		l.rotateFailed = err != nil
+		if err != nil {
+			// Retry after another maxBytes rather than on every write
+			l.size = 0
+		}
+	}
+
+	n, err := l.file.Write(p)
+	l.size += int64(n)
+	if err != nil && !l.writeFailed {
+		log.Printf("Failed to write %s, dropping output: %v", l.path, err)
+	}
+	l.writeFailed = err != nil
+	return len(p), nil
+}
+
+// rotate shifts path.N-1 to path.N, path to path.1 and starts a new file.
+// On failure the current file stays in use.
+func (l *rotatingLog) rotate() error {
+	for i := l.backups - 1; i >= 1; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", l.path, i), fmt.Sprintf("%s.%d", l.path, i+1))
+	}
+	if err := os.Rename(l.path, l.path+".1"); err != nil {
+		return fmt.Errorf("failed to rename log: %w", err)
+	}
+
+	old := l.file
+	if err := l.open(); err != nil {
+		return err
+	}
+	old.Close()
+	return nil
+}
+
+// Close closes the current file
+func (l *rotatingLog) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return nil
+	}
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
@@ -0,0 +1,53 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRotatingLogRotates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "app.log")
+	l, err := openRotatingLog(path, 10, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		if n, err := l.Write([]byte(line)); n != len(line) || err != nil {
+			t.Fatalf("write %q: %d, %v", line, n, err)
+		}
+	}
+
+	want := map[string]string{path: "fourth\n", path + ".1": "third\n", path + ".2": "second\n"}
+	for file, content := range want {
+		if data, _ := os.ReadFile(file); string(data) != content {
+			t.Errorf("%s: got %q, want %q", file, data, content)
+		}
+	}
+}
+
+func TestRotatingLogKeepsWritingWhenRotationFails(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	// A non-empty directory in the way of the first backup breaks the rename
+	if err := os.MkdirAll(filepath.Join(path+".1", "keep"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	l, err := openRotatingLog(path, 10, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	lines := []string{"first line\n", "second line\n", "third line\n"}
+	for _, line := range lines {
+		if n, err := l.Write([]byte(line)); n != len(line) || err != nil {
+			t.Fatalf("write %q: %d, %v", line, n, err)
+		}
+	}
+	if data, _ := os.ReadFile(path); string(data) != strings.Join(lines, "") {
+		t.Errorf("got %q, want every line in the current file", data)
+	}
+}
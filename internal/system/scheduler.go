@@ -0,0 +1,362 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"android-server-brain/config"
+	"android-server-brain/internal/i18n"
+	"android-server-brain/internal/storage"
+	"android-server-brain/internal/transport"
+)
+
+// Report modes of a scheduled task
+const (
+	ReportFailures = "failures"
+	ReportAll      = "all"
+)
+
+// defaultTaskTimeout applies to tasks added from chat
+const defaultTaskTimeout = 10 * time.Minute
+
+// maxReportOutput keeps the tail of the output within one Telegram message
+const maxReportOutput = 3000
+
+var (
+	ErrTaskNotFound = errors.New("task not found")
+	ErrConfigTask   = errors.New("task is defined in config.json")
+)
+
+// Task is a shell command run on a cron schedule
+type Task struct {
+	ID        string        `json:"id"`
+	Cron      string        `json:"cron"`
+	Command   string        `json:"command"`
+	Report    string        `json:"report"`     // failures or all
+	CreatedBy int64         `json:"created_by"` // receives the reports, 0 for config tasks
+	Timeout   time.Duration `json:"-"`
+	Config    bool          `json:"-"` // defined in config.json, can't be removed from chat
+
+	Next    time.Time `json:"-"`
+	LastRun *TaskRun  `json:"-"`
+	Running bool      `json:"-"`
+
+	schedule *CronSchedule
+}
+
+// TaskRun is the outcome of a task run
+type TaskRun struct {
+	Time     time.Time     `json:"time"`
+	Duration time.Duration `json:"duration"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// schedulerState is persisted across restarts
+type schedulerState struct {
+	NextID int                `json:"next_id"`
+	Tasks  []*Task            `json:"tasks"` // added from chat
+	Runs   map[string]TaskRun `json:"runs"`  // last run of every task
+}
+
+// Scheduler runs tasks from config.json and /schedule on their cron
+// schedule, through the same shell runner as /exec
+type Scheduler struct {
+	notifier  transport.Notifier
+	config    *config.Config
+	langs     *i18n.Store
+	watchdog  *Watchdog
+	heartbeat *Heartbeat
+	statePath string
+
+	mu     sync.Mutex
+	tasks  []*Task
+	nextID int
+	ctx    context.Context
+	wake   chan struct{}
+}
+
+// NewScheduler loads the configured and persisted tasks
+func NewScheduler(notifier transport.Notifier, cfg *config.Config, langs *i18n.Store, watchdog *Watchdog, heartbeat *Heartbeat) (*Scheduler, error) {
+	s := &Scheduler{
+		notifier:  notifier,
+		config:    cfg,
+		langs:     langs,
+		watchdog:  watchdog,
+		heartbeat: heartbeat,
+		statePath: cfg.StatePath("schedule.json"),
+		nextID:    1,
+		ctx:       context.Background(),
+		wake:      make(chan struct{}, 1),
+	}
+
+	for _, tc := range cfg.Schedule {
+		schedule, err := ParseCron(tc.Cron)
+		if err != nil {
+			return nil, fmt.Errorf("task %q: invalid cron %q: %v", tc.Name, tc.Cron, err)
+		}
+		s.tasks = append(s.tasks, &Task{
+			ID:       tc.Name,
+			Cron:     tc.Cron,
+			Command:  tc.Command,
+			Report:   tc.Report,
+			Timeout:  time.Duration(tc.TimeoutMinutes) * time.Minute,
+			Config:   true,
+			schedule: schedule,
+		})
+	}
+
+	var state schedulerState
+	if err := storage.LoadJSON(s.statePath, &state); err != nil {
+		log.Printf("Failed to load schedule: %v", err)
+	}
+	if state.NextID > s.nextID {
+		s.nextID = state.NextID
+	}
+	for _, t := range state.Tasks {
+		schedule, err := ParseCron(t.Cron)
+		if err != nil {
+			log.Printf("Dropping task %s with invalid cron %q: %v", t.ID, t.Cron, err)
+			continue
+		}
+		t.Timeout = defaultTaskTimeout
+		t.schedule = schedule
+		s.tasks = append(s.tasks, t)
+	}
+	for _, t := range s.tasks {
+		if run, ok := state.Runs[t.ID]; ok {
+			t.LastRun = &run
+		}
+	}
+
+	return s, nil
+}
+
+// Start runs due tasks until ctx is cancelled; running tasks are killed on shutdown
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	s.ctx = ctx
+	now := time.Now()
+	for _, t := range s.tasks {
+		t.Next = t.schedule.Next(now)
+	}
+	count := len(s.tasks)
+	s.mu.Unlock()
+
+	go func() {
+		for {
+			timer := time.NewTimer(time.Until(s.nextRun()))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-s.wake:
+				timer.Stop()
+			case <-timer.C:
+				s.runDue(time.Now())
+			}
+		}
+	}()
+	log.Printf("Scheduler started with %d tasks", count)
+}
+
+// nextRun returns the earliest next run, or an hour from now without tasks
+func (s *Scheduler) nextRun() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next := time.Now().Add(time.Hour)
+	for _, t := range s.tasks {
+		if !t.Next.IsZero() && t.Next.Before(next) {
+			next = t.Next
+		}
+	}
+	return next
+}
+
+// runDue starts every task whose time has come. A task still running from
+// its previous run is skipped.
+func (s *Scheduler) runDue(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, t := range s.tasks {
+		if t.Next.IsZero() || t.Next.After(now) {
+			continue
+		}
+		t.Next = t.schedule.Next(now)
+		if t.Running {
+			log.Printf("Task %s is still running, skipping this run", t.ID)
+			continue
+		}
+		t.Running = true
+		go s.run(t)
+	}
+}
+
+// run executes a task and reports the result
+func (s *Scheduler) run(t *Task) {
+	s.heartbeat.JobStarted(t.ID)
+	s.watchdog.RecordTask()
+
+	ctx, cancel := context.WithTimeout(s.ctx, t.Timeout)
+	start := time.Now()
+	output, err := ExecuteCommandContext(ctx, t.Command)
+	cancel()
+
+	// Killed by shutdown, not a real failure
+	if s.ctx.Err() != nil {
+		return
+	}
+
+	s.heartbeat.JobFinished(t.ID, err, output)
+
+	run := TaskRun{Time: start, Duration: time.Since(start).Round(time.Second)}
+	if err != nil {
+		run.Error = err.Error()
+		log.Printf("Task %s failed: %v", t.ID, err)
+	}
+
+	s.mu.Lock()
+	t.Running = false
+	t.LastRun = &run
+	s.mu.Unlock()
+	s.save()
+
+	if err != nil || t.Report == ReportAll {
+		s.report(t, run, err, output)
+	}
+}
+
+// report sends the result of a run to the task's creator, or to all admins
+func (s *Scheduler) report(t *Task, run TaskRun, err error, output string) {
+	recipients := s.config.AdminIDs()
+	if t.CreatedBy != 0 {
+		recipients = []int64{t.CreatedBy}
+	}
+
+	output = strings.TrimSpace(output)
+	if len(output) > maxReportOutput {
+		output = "…" + strings.ToValidUTF8(output[len(output)-maxReportOutput:], "")
+	}
+
+	for _, id := range recipients {
+		lang := s.langs.Get(id, "")
+		out := output
+		if out == "" {
+			out = i18n.T(lang, "schedule.no_output")
+		}
+
+		var text string
+		switch {
+		case errors.Is(err, context.DeadlineExceeded):
+			text = i18n.T(lang, "schedule.report_timeout", t.ID, t.Timeout, t.Command, out)
+		case err != nil:
+			text = i18n.T(lang, "schedule.report_failed", t.ID, run.Duration, err, t.Command, out)
+		default:
+			text = i18n.T(lang, "schedule.report_ok", t.ID, run.Duration, t.Command, out)
+		}
+
+		if _, err := s.notifier.Notify(id, text, transport.ModeMarkdown); err != nil {
+			log.Printf("Failed to send task report to %d: %v", id, err)
+		}
+	}
+}
+
+// Add schedules a new task and returns it
+func (s *Scheduler) Add(spec, command, report string, createdBy int64) (Task, error) {
+	schedule, err := ParseCron(spec)
+	if err != nil {
+		return Task{}, err
+	}
+
+	s.mu.Lock()
+	t := &Task{
+		ID:        strconv.Itoa(s.nextID),
+		Cron:      spec,
+		Command:   command,
+		Report:    report,
+		CreatedBy: createdBy,
+		Timeout:   defaultTaskTimeout,
+		Next:      schedule.Next(time.Now()),
+		schedule:  schedule,
+	}
+	s.nextID++
+	s.tasks = append(s.tasks, t)
+	added := *t
+	s.mu.Unlock()
+
+	s.save()
+	s.notify()
+	return added, nil
+}
+
+// Remove deletes a task added from chat
+func (s *Scheduler) Remove(id string) error {
+	s.mu.Lock()
+	index := -1
+	for i, t := range s.tasks {
+		if t.ID == id {
+			index = i
+		}
+	}
+	switch {
+	case index < 0:
+		s.mu.Unlock()
+		return ErrTaskNotFound
+	case s.tasks[index].Config:
+		s.mu.Unlock()
+		return ErrConfigTask
+	}
+	s.tasks = append(s.tasks[:index], s.tasks[index+1:]...)
+	s.mu.Unlock()
+
+	s.save()
+	s.notify()
+	return nil
+}
+
+// Tasks returns a snapshot of all tasks, config tasks first
+func (s *Scheduler) Tasks() []Task {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tasks := make([]Task, 0, len(s.tasks))
+	for _, t := range s.tasks {
+		tasks = append(tasks, *t)
+	}
+	return tasks
+}
+
+// notify wakes the loop to pick up a changed next run
+func (s *Scheduler) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// save persists the tasks added from chat and the last runs
+func (s *Scheduler) save() {
+	s.mu.Lock()
+	state := schedulerState{NextID: s.nextID, Runs: make(map[string]TaskRun)}
+	for _, t := range s.tasks {
+		if !t.Config {
+			state.Tasks = append(state.Tasks, t)
+		}
+		if t.LastRun != nil {
+			state.Runs[t.ID] = *t.LastRun
+		}
+	}
+	err := storage.SaveJSON(s.statePath, state)
+	s.mu.Unlock()
+
+	if err != nil {
+		log.Printf("Failed to save schedule: %v", err)
+	}
+}
@@ -0,0 +1,468 @@
+package system
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"android-server-brain/config"
+)
+
+var (
+	ErrNoServiceBackend = errors.New("no service manager found, install termux-services (pkg install termux-services) and restart Termux")
+	ErrUnknownService   = errors.New("unknown service")
+	// ErrAlreadyRunning and ErrNotRunning report a start or stop that had
+	// nothing to do; the result still carries the status
+	ErrAlreadyRunning = errors.New("already running")
+	ErrNotRunning     = errors.New("not running")
+)
+
+// Service states
+const (
+	ServiceUp      = "up"
+	ServiceDown    = "down"
+	ServiceFailed  = "failed" // could not be started at all
+	ServiceUnknown = "unknown"
+)
+
+// ServiceStatus is the state of a single service
+type ServiceStatus struct {
+	Name    string        `json:"name"`
+	Backend string        `json:"backend"`
+	State   string        `json:"state"`   // ServiceUp, ServiceDown, ServiceFailed or ServiceUnknown
+	PID     int           `json:"pid"`     // 0 when not running
+	Since   time.Duration `json:"since"`   // time spent in the current state
+	Enabled bool          `json:"enabled"` // started automatically
+}
+
+// Service actions accepted by ControlService
+const (
+	ActionStart   = "start"
+	ActionStop    = "stop"
+	ActionRestart = "restart"
+	ActionEnable  = "enable"
+	ActionDisable = "disable"
+)
+
+// serviceTimeout bounds a single service action
+const serviceTimeout = 10 * time.Second
+
+// ServiceResult is the outcome of a service action
+type ServiceResult struct {
+	Service string         `json:"service"`
+	Backend string         `json:"backend,omitempty"`
+	Action  string         `json:"action"`
+	Output  string         `json:"output,omitempty"` // what the service manager printed
+	Status  *ServiceStatus `json:"status,omitempty"` // state after the action, nil if unavailable
+}
+
+// ServiceBackend controls the services of one service manager
+type ServiceBackend interface {
+	// Name identifies the backend in messages, e.g. "runit"
+	Name() string
+	// Available reports whether the backend can be used on this device
+	Available() bool
+	// Has reports whether the backend manages the named service
+	Has(service string) bool
+
+	// List returns the status of every service, sorted by name
+	List(ctx context.Context) ([]ServiceStatus, error)
+
+	Start(ctx context.Context, service string) (string, error)
+	Stop(ctx context.Context, service string) (string, error)
+	Restart(ctx context.Context, service string) (string, error)
+	Status(ctx context.Context, service string) (ServiceStatus, error)
+
+	// Enable and Disable change whether the service starts automatically,
+	// without starting or stopping it
+	Enable(ctx context.Context, service string) (string, error)
+	Disable(ctx context.Context, service string) (string, error)
+}
+
+// ServiceLogger is implemented by backends that know where a service logs to
+type ServiceLogger interface {
+	LogFile(service string) string
+}
+
+// serviceBackends are the supported service managers, in lookup order
+var serviceBackends = []ServiceBackend{NewRunit()}
+
+// RegisterServiceBackend adds a backend that is looked up before the built-in ones
+func RegisterServiceBackend(b ServiceBackend) {
+	serviceBackends = append([]ServiceBackend{b}, serviceBackends...)
+}
+
+// availableBackends returns every usable service manager
+func availableBackends() []ServiceBackend {
+	var out []ServiceBackend
+	for _, b := range serviceBackends {
+		if b.Available() {
+			out = append(out, b)
+		}
+	}
+	return out
+}
+
+// FindService returns the backend that manages a service
+func FindService(service string) (ServiceBackend, error) {
+	backends := availableBackends()
+	if len(backends) == 0 {
+		return nil, ErrNoServiceBackend
+	}
+	for _, b := range backends {
+		if b.Has(service) {
+			return b, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownService, service)
+}
+
+// ListServices returns the services of all available backends
+func ListServices(ctx context.Context) ([]ServiceStatus, error) {
+	backends := availableBackends()
+	if len(backends) == 0 {
+		return nil, ErrNoServiceBackend
+	}
+
+	var all []ServiceStatus
+	for _, b := range backends {
+		list, err := b.List(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s services: %w", b.Name(), err)
+		}
+		all = append(all, list...)
+	}
+	return all, nil
+}
+
+// StartService starts a service through its service manager
+func StartService(service string) (ServiceResult, error) {
+	return ControlService(ActionStart, service)
+}
+
+// StopService stops a service through its service manager
+func StopService(service string) (ServiceResult, error) {
+	return ControlService(ActionStop, service)
+}
+
+// RestartService restarts a service through its service manager
+func RestartService(service string) (ServiceResult, error) {
+	return ControlService(ActionRestart, service)
+}
+
+// EnableService makes a service start automatically
+func EnableService(service string) (ServiceResult, error) {
+	return ControlService(ActionEnable, service)
+}
+
+// DisableService keeps a service from starting automatically
+func DisableService(service string) (ServiceResult, error) {
+	return ControlService(ActionDisable, service)
+}
+
+// ControlService runs a service action and returns its outcome along with
+// the state of the service afterwards
+func ControlService(action, service string) (ServiceResult, error) {
+	result := ServiceResult{Service: service, Action: action}
+	backend, err := FindService(service)
+	if err != nil {
+		return result, err
+	}
+	result.Backend = backend.Name()
+
+	ctx, cancel := context.WithTimeout(context.Background(), serviceTimeout)
+	defer cancel()
+
+	switch action {
+	case ActionStart:
+		result.Output, err = backend.Start(ctx, service)
+	case ActionStop:
+		result.Output, err = backend.Stop(ctx, service)
+	case ActionRestart:
+		result.Output, err = backend.Restart(ctx, service)
+	case ActionEnable:
+		result.Output, err = backend.Enable(ctx, service)
+	case ActionDisable:
+		result.Output, err = backend.Disable(ctx, service)
+	default:
+		return result, fmt.Errorf("unknown service action %q", action)
+	}
+	if err != nil && !errors.Is(err, ErrAlreadyRunning) && !errors.Is(err, ErrNotRunning) {
+		return result, err
+	}
+
+	if status, err := backend.Status(ctx, service); err == nil {
+		result.Status = &status
+	}
+	return result, err
+}
+
+// GetServiceStatus returns the state of a single service. A service whose
+// state can't be read is returned as ServiceUnknown along with the error.
+func GetServiceStatus(service string) (ServiceStatus, error) {
+	backend, err := FindService(service)
+	if err != nil {
+		return ServiceStatus{Name: service, State: ServiceUnknown}, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), serviceTimeout)
+	defer cancel()
+	return backend.Status(ctx, service)
+}
+
+// ServiceLogFile returns where a service logs to: its entry in service_logs,
+// or the log file of its backend
+func ServiceLogFile(cfg *config.Config, service string) (string, error) {
+	if path, ok := cfg.ServiceLogs[service]; ok {
+		return path, nil
+	}
+
+	b, err := FindService(service)
+	if err != nil {
+		return "", err
+	}
+	logger, ok := b.(ServiceLogger)
+	if !ok {
+		return "", fmt.Errorf("%s services have no log file", b.Name())
+	}
+	return logger.LogFile(service), nil
+}
+
+// ServiceLog returns the last lines of a service's log
+func ServiceLog(cfg *config.Config, service string, lines int) (string, error) {
+	path, err := ServiceLogFile(cfg, service)
+	if err != nil {
+		return "", err
+	}
+	return tailFile(path, lines)
+}
+
+// ReadLogFrom returns what was appended to a log after offset and the offset
+// to continue from. A log smaller than offset was rotated or truncated and
+// is read from the start; at most the last 256 KiB are returned.
+func ReadLogFrom(path string, offset int64) (string, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", offset, fmt.Errorf("failed to open log: %w", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return "", offset, fmt.Errorf("failed to read log: %w", err)
+	}
+	size := info.Size()
+	if size < offset {
+		offset = 0
+	}
+	if size-offset > maxLogRead {
+		offset = size - maxLogRead
+	}
+	if size == offset {
+		return "", offset, nil
+	}
+
+	data := make([]byte, size-offset)
+	n, err := f.ReadAt(data, offset)
+	if err != nil && err != io.EOF {
+		return "", offset, fmt.Errorf("failed to read log: %w", err)
+	}
+	return string(data[:n]), offset + int64(n), nil
+}
+
+// maxLogRead limits how much of a log is read at once
+const maxLogRead = 256 * 1024
+
+// tailFile returns up to n last lines of a file, reading at most its last 256 KiB
+func tailFile(path string, n int) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open log: %w", err)
+	}
+	defer f.Close()
+
+	if info, err := f.Stat(); err == nil && info.Size() > maxLogRead {
+		if _, err := f.Seek(-maxLogRead, io.SeekEnd); err != nil {
+			return "", fmt.Errorf("failed to read log: %w", err)
+		}
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), maxLogRead)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+		if len(lines) > n {
+			lines = lines[1:]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("failed to read log: %w", err)
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// termuxPrefix returns $PREFIX, or the default Termux prefix outside a Termux shell
+func termuxPrefix() string {
+	if prefix := os.Getenv("PREFIX"); prefix != "" {
+		return prefix
+	}
+	return "/data/data/com.termux/files/usr"
+}
+
+// Runit manages termux-services, runit services controlled with sv
+type Runit struct {
+	dir    string // service directory, $PREFIX/var/service
+	logDir string // svlogd output, $PREFIX/var/log/sv
+}
+
+// NewRunit creates the backend for the termux-services directories
+func NewRunit() *Runit {
+	prefix := termuxPrefix()
+	return &Runit{
+		dir:    filepath.Join(prefix, "var", "service"),
+		logDir: filepath.Join(prefix, "var", "log", "sv"),
+	}
+}
+
+func (r *Runit) Name() string {
+	return "runit"
+}
+
+// Available reports whether sv is installed and the service directory exists
+func (r *Runit) Available() bool {
+	if _, err := exec.LookPath("sv"); err != nil {
+		return false
+	}
+	info, err := os.Stat(r.dir)
+	return err == nil && info.IsDir()
+}
+
+// Has reports whether the service has a directory in the service dir.
+// Names with path elements are rejected so sv never leaves the directory.
+func (r *Runit) Has(service string) bool {
+	if service == "" || strings.HasPrefix(service, ".") || strings.ContainsAny(service, `/\`) {
+		return false
+	}
+	info, err := os.Stat(filepath.Join(r.dir, service))
+	return err == nil && info.IsDir()
+}
+
+// LogFile returns the current log file svlogd writes for the service
+func (r *Runit) LogFile(service string) string {
+	return filepath.Join(r.logDir, service, "current")
+}
+
+// List returns the status of every service directory
+func (r *Runit) List(ctx context.Context) ([]ServiceStatus, error) {
+	entries, err := os.ReadDir(r.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var list []ServiceStatus
+	for _, e := range entries {
+		if !r.Has(e.Name()) {
+			continue
+		}
+		// A service whose supervisor isn't running is listed as unknown
+		status, _ := r.Status(ctx, e.Name())
+		list = append(list, status)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+func (r *Runit) Start(ctx context.Context, service string) (string, error) {
+	return r.sv(ctx, "up", service)
+}
+
+func (r *Runit) Stop(ctx context.Context, service string) (string, error) {
+	return r.sv(ctx, "down", service)
+}
+
+func (r *Runit) Restart(ctx context.Context, service string) (string, error) {
+	return r.sv(ctx, "restart", service)
+}
+
+// Enable removes the "down" file so runsv starts the service again
+func (r *Runit) Enable(ctx context.Context, service string) (string, error) {
+	if !r.Has(service) {
+		return "", fmt.Errorf("%w: %s", ErrUnknownService, service)
+	}
+	err := os.Remove(filepath.Join(r.dir, service, "down"))
+	if err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to enable %s: %w", service, err)
+	}
+	return "", nil
+}
+
+// Disable creates the "down" file that keeps runsv from starting the service
+func (r *Runit) Disable(ctx context.Context, service string) (string, error) {
+	if !r.Has(service) {
+		return "", fmt.Errorf("%w: %s", ErrUnknownService, service)
+	}
+	if err := os.WriteFile(filepath.Join(r.dir, service, "down"), nil, 0644); err != nil {
+		return "", fmt.Errorf("failed to disable %s: %w", service, err)
+	}
+	return "", nil
+}
+
+// svStatus matches the service part of `sv status`, e.g.
+// "run: sshd: (pid 123) 3600s" or "down: sshd: 12s, normally up"
+var svStatus = regexp.MustCompile(`^(run|down|finish): [^:]+: (?:\(pid (\d+)\) )?(\d+)s`)
+
+func (r *Runit) Status(ctx context.Context, service string) (ServiceStatus, error) {
+	status := ServiceStatus{Name: service, Backend: r.Name(), State: ServiceUnknown}
+	if !r.Has(service) {
+		return status, fmt.Errorf("%w: %s", ErrUnknownService, service)
+	}
+
+	// A "down" file keeps runsv from starting the service, sv-disable creates it
+	_, err := os.Stat(filepath.Join(r.dir, service, "down"))
+	status.Enabled = os.IsNotExist(err)
+
+	output, err := r.sv(ctx, "status", service)
+	if err != nil {
+		return status, fmt.Errorf("%v: %s", err, output)
+	}
+
+	m := svStatus.FindStringSubmatch(output)
+	if m == nil {
+		return status, fmt.Errorf("unexpected sv status output: %s", output)
+	}
+	if m[1] == "down" {
+		status.State = ServiceDown
+	} else {
+		status.State = ServiceUp
+	}
+	status.PID, _ = strconv.Atoi(m[2])
+	seconds, _ := strconv.Atoi(m[3])
+	status.Since = time.Duration(seconds) * time.Second
+	return status, nil
+}
+
+// sv runs an sv command against the termux-services directory
+func (r *Runit) sv(ctx context.Context, action, service string) (string, error) {
+	if !r.Has(service) {
+		return "", fmt.Errorf("%w: %s", ErrUnknownService, service)
+	}
+
+	cmd := exec.CommandContext(ctx, "sv", action, service)
+	cmd.Env = append(os.Environ(), "SVDIR="+r.dir)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return strings.TrimSpace(string(output)), fmt.Errorf("sv %s %s failed: %v", action, service, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
@@ -0,0 +1,143 @@
+package system
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"android-server-brain/config"
+	"android-server-brain/internal/i18n"
+	"android-server-brain/internal/transport"
+)
+
+// telegramSink sends alerts to a Telegram user or group chat
+type telegramSink struct {
+	notifier transport.Notifier
+	langs    *i18n.Store
+	chatID   int64
+}
+
+// Send implements Sink
+func (s *telegramSink) Send(ctx context.Context, n Notification) error {
+	lang := s.langs.Get(s.chatID, "")
+
+	text := n.Message.Render(lang)
+	if n.Escalated > 0 {
+		text = i18n.T(lang, "alert.escalated", n.Escalated) + text
+	}
+
+	keyboard := transport.Keyboard{transport.Row(
+		transport.Button{Text: i18n.T(lang, "mute.snooze_button"), Unique: ButtonSnooze, Data: n.Check},
+		transport.Button{Text: i18n.T(lang, "mute.tomorrow_button"), Unique: ButtonMute, Data: n.Check},
+	)}
+	if n.Ackable {
+		keyboard = append(keyboard, transport.Row(
+			transport.Button{Text: i18n.T(lang, "alert.ack_button"), Unique: ButtonAck, Data: n.ID},
+		))
+	}
+
+	_, err := s.notifier.Notify(s.chatID, text, transport.ModeMarkdown, keyboard)
+	return err
+}
+
+// webhookPayload is the JSON body posted to webhook sinks
+type webhookPayload struct {
+	ID        string    `json:"id"`
+	Check     string    `json:"check"`
+	Severity  string    `json:"severity"`
+	Key       string    `json:"key"`
+	Text      string    `json:"text"`
+	Time      time.Time `json:"time"`
+	Escalated bool      `json:"escalated"`
+}
+
+// webhookSink posts alerts as JSON to a URL
+type webhookSink struct {
+	url    string
+	client *http.Client
+}
+
+func newWebhookSink(url string) *webhookSink {
+	return &webhookSink{url: url, client: &http.Client{Timeout: sinkTimeout}}
+}
+
+// Send implements Sink
+func (s *webhookSink) Send(ctx context.Context, n Notification) error {
+	body, err := json.Marshal(webhookPayload{
+		ID:        n.ID,
+		Check:     n.Check,
+		Severity:  n.Severity.String(),
+		Key:       n.Message.Key,
+		Text:      transport.StripMarkdown(n.Message.Render(i18n.Default)),
+		Time:      n.Time,
+		Escalated: n.Escalated > 0,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post alert: %w", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// smtpSink emails alerts
+type smtpSink struct {
+	config config.SMTPConfig
+}
+
+// Send implements Sink
+func (s *smtpSink) Send(ctx context.Context, n Notification) error {
+	c := s.config
+	text := transport.StripMarkdown(n.Message.Render(i18n.Default))
+	subject := fmt.Sprintf("[ASB] %s: %s", strings.ToUpper(n.Severity.String()), n.Check)
+	if n.Escalated > 0 {
+		subject = "[ESCALATED] " + subject
+	}
+
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", c.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(c.To, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", subject)
+	fmt.Fprintf(&msg, "Date: %s\r\n", n.Time.Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(text, "\n", "\r\n"))
+	msg.WriteString("\r\n")
+
+	var auth smtp.Auth
+	if c.Username != "" {
+		auth = smtp.PlainAuth("", c.Username, c.Password, c.Host)
+	}
+
+	// net/smtp has no context support; run it so the caller isn't blocked past the deadline
+	addr := fmt.Sprintf("%s:%d", c.Host, c.Port)
+	errc := make(chan error, 1)
+	go func() { errc <- smtp.SendMail(addr, auth, c.From, c.To, []byte(msg.String())) }()
+	select {
+	case err := <-errc:
+		if err != nil {
+			return fmt.Errorf("failed to send mail: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
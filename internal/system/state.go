@@ -0,0 +1,228 @@
+package system
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"time"
+
+	"android-server-brain/internal/storage"
+)
+
+const (
+	// maxHistory and historyAge bound the persisted alert history
+	maxHistory = 500
+	historyAge = 8 * 24 * time.Hour
+
+	// restartMarkerAge is how long a restart marker carries the start time
+	// over; an older one is left from a restart that never came back
+	restartMarkerAge = 5 * time.Minute
+)
+
+// StatefulCheck is a check whose alert state survives restarts, so that
+// alerts already sent aren't repeated after ASB comes back up
+type StatefulCheck interface {
+	Check
+	// SaveState returns the alert state to persist; it must be JSON encodable
+	SaveState() interface{}
+	// RestoreState loads a state previously returned by SaveState
+	RestoreState(data json.RawMessage) error
+}
+
+// AlertRecord is an entry of the alert history
+type AlertRecord struct {
+	Time       time.Time   `json:"time"`
+	Check      string      `json:"check"`
+	Severity   CheckStatus `json:"severity"`
+	Key        string      `json:"key"`
+	Text       string      `json:"text"`
+	Suppressed bool        `json:"suppressed,omitempty"`
+}
+
+// checkSnapshot is the persisted part of a check's scheduling state
+type checkSnapshot struct {
+	LastRun   time.Time       `json:"last_run"`
+	LastAlert time.Time       `json:"last_alert"`
+	Status    CheckStatus     `json:"status"`
+	State     json.RawMessage `json:"state,omitempty"`
+}
+
+// watchdogState is the content of the watchdog state file
+type watchdogState struct {
+	StartTime time.Time                `json:"start_time"`
+	Mutes     map[string]time.Time     `json:"mutes"`
+	History   []AlertRecord            `json:"history"`
+	Checks    map[string]checkSnapshot `json:"checks"`
+	Stats     []*DayStats              `json:"stats"`
+}
+
+// restartMarker is written right before ASB restarts itself, so that the
+// next start keeps counting uptime from the previous start time
+type restartMarker struct {
+	Time time.Time `json:"time"`
+}
+
+// MarkRestart saves the state and marks the next start as a restart of
+// this run; uptime is reset on any other start
+func (w *Watchdog) MarkRestart() error {
+	w.save()
+	return storage.SaveJSON(w.restartPath(), restartMarker{Time: time.Now()})
+}
+
+// restartPath is the path of the one-shot restart marker
+func (w *Watchdog) restartPath() string {
+	return w.config.StatePath("restart.json")
+}
+
+// restarted consumes the restart marker and reports whether it is recent
+func (w *Watchdog) restarted() bool {
+	var marker restartMarker
+	err := storage.LoadJSON(w.restartPath(), &marker)
+	if err == nil && marker.Time.IsZero() {
+		return false // no marker
+	}
+	if err := os.Remove(w.restartPath()); err != nil {
+		log.Printf("Failed to remove restart marker: %v", err)
+	}
+	if err != nil {
+		log.Printf("Failed to load restart marker: %v", err)
+		return false
+	}
+	return time.Since(marker.Time) < restartMarkerAge
+}
+
+// load restores the state file; checks pick up their snapshot in AddCheck
+func (w *Watchdog) load() {
+	restarted := w.restarted()
+
+	var state watchdogState
+	if err := storage.LoadJSON(w.statePath, &state); err != nil {
+		log.Printf("Failed to load watchdog state: %v", err)
+		return
+	}
+
+	// Uptime only survives a restart ASB made itself, like /update now
+	if restarted && !state.StartTime.IsZero() {
+		w.startTime = state.StartTime
+	}
+	for name, until := range state.Mutes {
+		w.mutes[name] = until
+	}
+	w.history = state.History
+	for _, day := range state.Stats {
+		// Buckets are written to after loading; missing maps would panic
+		day.DiskFirst = copyMap(day.DiskFirst)
+		day.DiskLast = copyMap(day.DiskLast)
+		if day.Failed == nil {
+			day.Failed = make(map[string]int)
+		}
+	}
+	w.stats = state.Stats
+	w.restored = state.Checks
+}
+
+// restore applies a persisted snapshot to a newly added check
+func (w *Watchdog) restore(st *checkState) {
+	snap, ok := w.restored[st.check.Name()]
+	if !ok || snap.LastRun.IsZero() {
+		return
+	}
+
+	if sc, ok := st.check.(StatefulCheck); ok && len(snap.State) > 0 {
+		if err := sc.RestoreState(snap.State); err != nil {
+			log.Printf("Failed to restore %s check state: %v", st.check.Name(), err)
+			return
+		}
+		st.state = snap.State
+	}
+
+	st.lastRun = snap.LastRun
+	st.lastAlert = snap.LastAlert
+	st.lastResult = CheckResult{Status: snap.Status, Summary: Msg("check.restored")}
+	// Keep the schedule; checks that became due while ASB was down run right away
+	st.nextRun = snap.LastRun.Add(st.interval)
+	if now := time.Now(); st.nextRun.Before(now) {
+		st.nextRun = now
+	}
+}
+
+// saveState captures the alert state of a check after it ran. It is called
+// from the goroutine running the check, so the check isn't mutated meanwhile.
+func saveState(c Check) json.RawMessage {
+	sc, ok := c.(StatefulCheck)
+	if !ok {
+		return nil
+	}
+	data, err := json.Marshal(sc.SaveState())
+	if err != nil {
+		log.Printf("Failed to encode %s check state: %v", c.Name(), err)
+		return nil
+	}
+	return data
+}
+
+// record appends alerts to the history, dropping old entries
+func (w *Watchdog) record(records ...AlertRecord) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.history = append(w.history, records...)
+
+	cutoff := time.Now().Add(-historyAge)
+	start := 0
+	for start < len(w.history) && (w.history[start].Time.Before(cutoff) || len(w.history)-start > maxHistory) {
+		start++
+	}
+	w.history = w.history[start:]
+}
+
+// History returns the alerts raised since the given time, oldest first
+func (w *Watchdog) History(since time.Time) []AlertRecord {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var records []AlertRecord
+	for _, r := range w.history {
+		if !r.Time.Before(since) {
+			records = append(records, r)
+		}
+	}
+	return records
+}
+
+// save writes the watchdog state file
+func (w *Watchdog) save() {
+	w.mu.Lock()
+	state := watchdogState{
+		StartTime: w.startTime,
+		Mutes:     make(map[string]time.Time, len(w.mutes)),
+		History:   append([]AlertRecord(nil), w.history...),
+		Stats:     make([]*DayStats, len(w.stats)),
+		Checks:    make(map[string]checkSnapshot, len(w.checks)),
+	}
+	for name, until := range w.mutes {
+		state.Mutes[name] = until
+	}
+	for i, day := range w.stats {
+		state.Stats[i] = day.clone()
+	}
+	for _, st := range w.checks {
+		if st.lastRun.IsZero() {
+			continue
+		}
+		state.Checks[st.check.Name()] = checkSnapshot{
+			LastRun:   st.lastRun,
+			LastAlert: st.lastAlert,
+			Status:    st.lastResult.Status,
+			State:     st.state,
+		}
+	}
+	w.mu.Unlock()
+
+	// Concurrent saves share the same temporary file
+	w.saveMu.Lock()
+	defer w.saveMu.Unlock()
+	if err := storage.SaveJSON(w.statePath, state); err != nil {
+		log.Printf("Failed to save watchdog state: %v", err)
+	}
+}
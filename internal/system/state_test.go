@@ -0,0 +1,41 @@
+package system
+
+import (
+	"testing"
+	"time"
+
+	"android-server-brain/config"
+	"android-server-brain/internal/i18n"
+	"android-server-brain/internal/transport/memory"
+)
+
+func TestStartTimeKeptOnlyAcrossMarkedRestarts(t *testing.T) {
+	cfg := &config.Config{StorageDir: t.TempDir()}
+	newWatchdog := func() *Watchdog {
+		return NewWatchdog(memory.New(), cfg, i18n.NewStore(""), time.Minute)
+	}
+
+	first := newWatchdog()
+	first.save()
+	time.Sleep(10 * time.Millisecond)
+
+	// A plain start, e.g. after a crash or reboot, resets the uptime
+	second := newWatchdog()
+	if !second.StartTime().After(first.StartTime()) {
+		t.Errorf("start time %v kept after an unmarked start", second.StartTime())
+	}
+
+	if err := second.MarkRestart(); err != nil {
+		t.Fatal(err)
+	}
+	third := newWatchdog()
+	if !third.StartTime().Equal(second.StartTime()) {
+		t.Errorf("got start time %v, want %v after a marked restart", third.StartTime(), second.StartTime())
+	}
+
+	// The marker is used only once
+	third.save()
+	if fourth := newWatchdog(); fourth.StartTime().Equal(second.StartTime()) {
+		t.Error("restart marker used twice")
+	}
+}
@@ -0,0 +1,214 @@
+package system
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// statsDays is how many daily buckets are kept, enough for a weekly digest
+const statsDays = 8
+
+// DayStats aggregates watchdog data over one calendar day
+type DayStats struct {
+	Date           string             `json:"date"` // 2006-01-02 in the digest timezone
+	BatterySamples int                `json:"battery_samples"`
+	BatteryMin     float64            `json:"battery_min"`
+	BatteryMax     float64            `json:"battery_max"`
+	TempMax        float64            `json:"temp_max"`
+	ChargeCycles   int                `json:"charge_cycles"`
+	DiskFirst      map[string]float64 `json:"disk_first"` // used bytes by path
+	DiskLast       map[string]float64 `json:"disk_last"`
+	Execs          int                `json:"execs"`  // /exec commands
+	Tasks          int                `json:"tasks"`  // scheduled task runs
+	Failed         map[string]int     `json:"failed"` // runs not OK by check
+	Restarts       int                `json:"restarts"`
+}
+
+// clone copies the bucket so it can be encoded outside the lock
+func (d *DayStats) clone() *DayStats {
+	c := *d
+	c.DiskFirst = copyMap(d.DiskFirst)
+	c.DiskLast = copyMap(d.DiskLast)
+	c.Failed = make(map[string]int, len(d.Failed))
+	for k, v := range d.Failed {
+		c.Failed[k] = v
+	}
+	return &c
+}
+
+func copyMap(m map[string]float64) map[string]float64 {
+	c := make(map[string]float64, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
+
+// today returns the bucket for now, creating it and dropping old ones.
+// Callers must hold w.mu.
+func (w *Watchdog) today() *DayStats {
+	date := time.Now().In(w.config.Digest.Location()).Format("2006-01-02")
+	if n := len(w.stats); n > 0 && w.stats[n-1].Date == date {
+		return w.stats[n-1]
+	}
+
+	day := &DayStats{
+		Date:      date,
+		DiskFirst: make(map[string]float64),
+		DiskLast:  make(map[string]float64),
+		Failed:    make(map[string]int),
+	}
+	w.stats = append(w.stats, day)
+	if len(w.stats) > statsDays {
+		w.stats = w.stats[len(w.stats)-statsDays:]
+	}
+	return day
+}
+
+// recordStats adds a check result to today's statistics. Callers must hold w.mu.
+func (w *Watchdog) recordStats(check string, result CheckResult) {
+	day := w.today()
+	if result.Status == StatusWarning || result.Status == StatusCritical {
+		day.Failed[check]++
+	}
+
+	m := result.Metrics
+	if percent, ok := m[MetricBatteryPercent]; ok {
+		if day.BatterySamples == 0 || percent < day.BatteryMin {
+			day.BatteryMin = percent
+		}
+		if day.BatterySamples == 0 || percent > day.BatteryMax {
+			day.BatteryMax = percent
+		}
+		day.BatterySamples++
+	}
+	if temp, ok := m[MetricBatteryTemp]; ok && temp > day.TempMax {
+		day.TempMax = temp
+	}
+	if charging, ok := m[MetricBatteryCharging]; ok {
+		// A charge cycle starts whenever the charger gets connected
+		if charging == 1 && w.charging == 0 {
+			day.ChargeCycles++
+		}
+		w.charging = int(charging)
+	}
+	day.Restarts += int(m[MetricRestarts])
+
+	for name, value := range m {
+		if path := strings.TrimPrefix(name, MetricDiskUsedPrefix); path != name {
+			if _, ok := day.DiskFirst[path]; !ok {
+				day.DiskFirst[path] = value
+			}
+			day.DiskLast[path] = value
+		}
+	}
+}
+
+// RecordExec counts a /exec command for the digest
+func (w *Watchdog) RecordExec() {
+	w.mu.Lock()
+	w.today().Execs++
+	w.mu.Unlock()
+}
+
+// RecordTask counts a scheduled task run for the digest
+func (w *Watchdog) RecordTask() {
+	w.mu.Lock()
+	w.today().Tasks++
+	w.mu.Unlock()
+}
+
+// RecordRestart counts a restart of a supervised program for the digest
+func (w *Watchdog) RecordRestart() {
+	w.mu.Lock()
+	w.today().Restarts++
+	w.mu.Unlock()
+}
+
+// PeriodStats combines the daily buckets of [from, to)
+type PeriodStats struct {
+	Days           int
+	BatterySamples int
+	BatteryMin     float64
+	BatteryMax     float64
+	TempMax        float64
+	ChargeCycles   int
+	DiskChange     map[string]float64 // used bytes growth by path
+	DiskLast       map[string]float64
+	Execs          int
+	Tasks          int
+	Failed         map[string]int
+	Restarts       int
+}
+
+// FailedChecks returns the names of checks that failed, most failures first
+func (p PeriodStats) FailedChecks() []string {
+	names := make([]string, 0, len(p.Failed))
+	for name := range p.Failed {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if p.Failed[names[i]] != p.Failed[names[j]] {
+			return p.Failed[names[i]] > p.Failed[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// Stats aggregates the statistics of the days between from (inclusive) and to (exclusive)
+func (w *Watchdog) Stats(from, to time.Time) PeriodStats {
+	loc := w.config.Digest.Location()
+	first := from.In(loc).Format("2006-01-02")
+	last := to.In(loc).Format("2006-01-02")
+
+	p := PeriodStats{
+		DiskChange: make(map[string]float64),
+		DiskLast:   make(map[string]float64),
+		Failed:     make(map[string]int),
+	}
+	diskFirst := make(map[string]float64)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, day := range w.stats {
+		if day.Date < first || day.Date >= last {
+			continue
+		}
+		p.Days++
+		if day.BatterySamples > 0 {
+			if p.BatterySamples == 0 || day.BatteryMin < p.BatteryMin {
+				p.BatteryMin = day.BatteryMin
+			}
+			if p.BatterySamples == 0 || day.BatteryMax > p.BatteryMax {
+				p.BatteryMax = day.BatteryMax
+			}
+			p.BatterySamples += day.BatterySamples
+		}
+		if day.TempMax > p.TempMax {
+			p.TempMax = day.TempMax
+		}
+		p.ChargeCycles += day.ChargeCycles
+		p.Execs += day.Execs
+		p.Tasks += day.Tasks
+		p.Restarts += day.Restarts
+		for name, n := range day.Failed {
+			p.Failed[name] += n
+		}
+		for path, used := range day.DiskFirst {
+			if _, ok := diskFirst[path]; !ok {
+				diskFirst[path] = used
+			}
+		}
+		for path, used := range day.DiskLast {
+			p.DiskLast[path] = used
+		}
+	}
+
+	for path, used := range p.DiskLast {
+		p.DiskChange[path] = used - diskFirst[path]
+	}
+	return p
+}
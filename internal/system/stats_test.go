@@ -0,0 +1,59 @@
+package system
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"android-server-brain/config"
+	"android-server-brain/internal/i18n"
+	"android-server-brain/internal/transport/memory"
+)
+
+func TestScheduledRunsAreNotExecs(t *testing.T) {
+	cfg := &config.Config{StorageDir: t.TempDir()}
+	watchdog := NewWatchdog(memory.New(), cfg, i18n.NewStore(""), time.Minute)
+	s, err := NewScheduler(memory.New(), cfg, i18n.NewStore(""), watchdog, NewHeartbeat(cfg.Heartbeat, watchdog))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s.Start(ctx)
+	if _, err := s.Add("* * * * *", "true", ReportFailures, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	watchdog.RecordExec()
+	s.runDue(time.Now().Add(time.Hour))
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		stats := watchdog.Stats(time.Now(), time.Now().AddDate(0, 0, 1))
+		if stats.Tasks == 1 {
+			if stats.Execs != 1 {
+				t.Errorf("got %d execs, want only the /exec run", stats.Execs)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("got %d task runs, want 1", stats.Tasks)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestDigestDatesFollowLanguage(t *testing.T) {
+	cfg := &config.Config{StorageDir: t.TempDir()}
+	watchdog := NewWatchdog(memory.New(), cfg, i18n.NewStore(""), time.Minute)
+	d := NewDigest(memory.New(), cfg, i18n.NewStore(""), watchdog)
+	now := time.Date(2026, time.March, 10, 9, 0, 0, 0, time.Local)
+
+	if text := d.Report(i18n.English, true, now); !strings.Contains(text, "Mar 3 – Mar 9") {
+		t.Errorf("English weekly title: %q", text)
+	}
+	if text := d.Report(i18n.Russian, false, now); !strings.Contains(text, "09.03") || strings.Contains(text, "Mar") {
+		t.Errorf("Russian daily title: %q", text)
+	}
+}
@@ -0,0 +1,390 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	"android-server-brain/config"
+	"android-server-brain/internal/storage"
+)
+
+// Restart policies of supervised programs
+const (
+	RestartAlways    = "always"
+	RestartOnFailure = "on-failure"
+	RestartNever     = "never"
+)
+
+const (
+	programBackoffMin = time.Second
+	programBackoffMax = time.Minute
+	// programStable resets the backoff once a program has run this long
+	programStable = time.Minute
+	// programWaitDelay bounds the wait for output after a program exited:
+	// the log is written through a pipe that a detached child keeps open
+	programWaitDelay = 2 * time.Second
+)
+
+// Supervisor runs the programs declared in config.json and restarts them
+// according to their policy. It is the "asb" service backend.
+type Supervisor struct {
+	statePath string
+	watchdog  *Watchdog // restarts are counted in its statistics
+
+	mu       sync.Mutex
+	programs map[string]*program
+	wg       sync.WaitGroup
+}
+
+// program is the state of one supervised program, guarded by Supervisor.mu
+type program struct {
+	config  config.ProgramConfig
+	logPath string
+
+	enabled bool          // started with ASB, from config unless changed in chat
+	active  bool          // the run loop is alive, possibly waiting to restart
+	failed  bool          // the last launch couldn't start the program
+	pid     int           // 0 when the process isn't running
+	changed time.Time     // last start or exit
+	stop    chan struct{} // closed to end the run loop, nil once closed
+	done    chan struct{} // closed when the run loop has exited
+}
+
+// NewSupervisor creates the supervisor for cfg.Programs; nothing is
+// started until StartAll
+func NewSupervisor(cfg *config.Config, watchdog *Watchdog) *Supervisor {
+	s := &Supervisor{statePath: cfg.StatePath("programs.json"), watchdog: watchdog, programs: make(map[string]*program)}
+
+	// Autostart changed with /svc enable|disable, by program name
+	autostart := make(map[string]bool)
+	if err := storage.LoadJSON(s.statePath, &autostart); err != nil {
+		log.Printf("Failed to load program state: %v", err)
+	}
+
+	for _, pc := range cfg.Programs {
+		logPath := pc.LogFile
+		if logPath == "" {
+			logPath = cfg.StatePath("logs/" + pc.Name + ".log")
+		}
+		enabled, ok := autostart[pc.Name]
+		if !ok {
+			enabled = !pc.Disabled
+		}
+		s.programs[pc.Name] = &program{config: pc, logPath: logPath, enabled: enabled, changed: time.Now()}
+	}
+	return s
+}
+
+func (s *Supervisor) Name() string {
+	return "asb"
+}
+
+// Available reports whether any programs are configured
+func (s *Supervisor) Available() bool {
+	return len(s.programs) > 0
+}
+
+func (s *Supervisor) Has(service string) bool {
+	_, ok := s.programs[service]
+	return ok
+}
+
+// LogFile returns the log the program's output is written to
+func (s *Supervisor) LogFile(service string) string {
+	if p, ok := s.programs[service]; ok {
+		return p.logPath
+	}
+	return ""
+}
+
+// StartAll starts every program that isn't disabled
+func (s *Supervisor) StartAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, p := range s.programs {
+		if p.enabled && !p.active {
+			s.launch(p)
+		}
+	}
+}
+
+// Shutdown stops all programs and waits for them to exit
+func (s *Supervisor) Shutdown() {
+	s.mu.Lock()
+	for _, p := range s.programs {
+		if p.stop != nil {
+			close(p.stop)
+			p.stop = nil
+		}
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+}
+
+func (s *Supervisor) List(ctx context.Context) ([]ServiceStatus, error) {
+	var list []ServiceStatus
+	for name := range s.programs {
+		status, _ := s.Status(ctx, name)
+		list = append(list, status)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+func (s *Supervisor) Status(ctx context.Context, service string) (ServiceStatus, error) {
+	status := ServiceStatus{Name: service, Backend: s.Name(), State: ServiceUnknown}
+	p, ok := s.programs[service]
+	if !ok {
+		return status, fmt.Errorf("%w: %s", ErrUnknownService, service)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status.Enabled = p.enabled
+	status.PID = p.pid
+	status.Since = time.Since(p.changed).Truncate(time.Second)
+	switch {
+	case p.pid != 0:
+		status.State = ServiceUp
+	case p.failed:
+		status.State = ServiceFailed
+	default:
+		status.State = ServiceDown
+	}
+	return status, nil
+}
+
+func (s *Supervisor) Start(ctx context.Context, service string) (string, error) {
+	p, ok := s.programs[service]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnknownService, service)
+	}
+
+	s.mu.Lock()
+	if p.active {
+		s.mu.Unlock()
+		return "", ErrAlreadyRunning
+	}
+	launched := s.launch(p)
+	s.mu.Unlock()
+
+	// Wait for the first attempt so the status afterwards is accurate
+	select {
+	case err := <-launched:
+		if err != nil {
+			return "", err
+		}
+	case <-ctx.Done():
+	}
+	return "", nil
+}
+
+func (s *Supervisor) Stop(ctx context.Context, service string) (string, error) {
+	p, ok := s.programs[service]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnknownService, service)
+	}
+
+	s.mu.Lock()
+	if !p.active {
+		s.mu.Unlock()
+		return "", ErrNotRunning
+	}
+	if p.stop != nil {
+		close(p.stop)
+		p.stop = nil
+	}
+	done := p.done
+	s.mu.Unlock()
+
+	// Not bound to ctx: the wait is already limited by the stop timeout
+	<-done
+	return "", nil
+}
+
+func (s *Supervisor) Restart(ctx context.Context, service string) (string, error) {
+	if _, err := s.Stop(ctx, service); err != nil && !errors.Is(err, ErrNotRunning) {
+		return "", err
+	}
+	return s.Start(ctx, service)
+}
+
+func (s *Supervisor) Enable(ctx context.Context, service string) (string, error) {
+	return s.setAutostart(service, true)
+}
+
+func (s *Supervisor) Disable(ctx context.Context, service string) (string, error) {
+	return s.setAutostart(service, false)
+}
+
+// setAutostart changes whether a program starts with ASB and saves the
+// programs whose setting differs from config.json
+func (s *Supervisor) setAutostart(service string, enabled bool) (string, error) {
+	p, ok := s.programs[service]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnknownService, service)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p.enabled = enabled
+
+	autostart := make(map[string]bool)
+	for name, p := range s.programs {
+		if p.enabled == p.config.Disabled {
+			autostart[name] = p.enabled
+		}
+	}
+	if err := storage.SaveJSON(s.statePath, autostart); err != nil {
+		return "", fmt.Errorf("failed to save program state: %w", err)
+	}
+	return "", nil
+}
+
+// launch starts the run loop of a program, the caller holds mu. The
+// returned channel receives the result of the first start attempt.
+func (s *Supervisor) launch(p *program) <-chan error {
+	p.active, p.failed = true, false
+	p.stop = make(chan struct{})
+	p.done = make(chan struct{})
+	launched := make(chan error, 1)
+	s.wg.Add(1)
+	go s.run(p, p.stop, p.done, launched)
+	return launched
+}
+
+// run starts the program and restarts it by its policy until stop is closed.
+// A program that can't be started the first time is left failed.
+func (s *Supervisor) run(p *program, stop, done chan struct{}, launched chan<- error) {
+	defer s.wg.Done()
+	defer close(done)
+	defer func() {
+		s.mu.Lock()
+		p.active, p.pid, p.changed = false, 0, time.Now()
+		s.mu.Unlock()
+	}()
+
+	out, err := openRotatingLog(p.logPath, int64(p.config.LogMaxKB)*1024, p.config.LogBackups)
+	if err != nil {
+		s.startFailed(p, launched, err)
+		return
+	}
+	defer out.Close()
+
+	env := os.Environ()
+	for k, v := range p.config.Env {
+		env = append(env, k+"="+v)
+	}
+
+	backoff := programBackoffMin
+	for first := true; ; first = false {
+		started := time.Now()
+		cmd := exec.Command("sh", "-c", p.config.Command)
+		cmd.Dir = p.config.Dir
+		cmd.Env = env
+		cmd.Stdout = out
+		cmd.Stderr = out
+		cmd.WaitDelay = programWaitDelay
+		// A process group of its own lets stop reach the children of sh
+		cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+
+		fmt.Fprintf(out, "%s [asb] starting: %s\n", started.Format(time.RFC3339), p.config.Command)
+		err := cmd.Start()
+		if err != nil && first {
+			fmt.Fprintf(out, "%s [asb] failed to start: %v\n", time.Now().Format(time.RFC3339), err)
+			s.startFailed(p, launched, err)
+			return
+		}
+		if err == nil {
+			s.mu.Lock()
+			p.pid, p.changed = cmd.Process.Pid, started
+			s.mu.Unlock()
+		}
+		if first {
+			launched <- nil
+		}
+
+		if err == nil {
+			exited := make(chan error, 1)
+			go func() { exited <- cmd.Wait() }()
+
+			select {
+			case err = <-exited:
+				// Leftover children still holding the log don't make a clean exit a failure
+				if errors.Is(err, exec.ErrWaitDelay) {
+					err = nil
+				}
+			case <-stop:
+				terminate(cmd.Process.Pid, time.Duration(p.config.StopTimeoutSeconds)*time.Second, exited)
+				fmt.Fprintf(out, "%s [asb] stopped\n", time.Now().Format(time.RFC3339))
+				return
+			}
+		}
+
+		s.mu.Lock()
+		p.pid, p.changed = 0, time.Now()
+		s.mu.Unlock()
+		fmt.Fprintf(out, "%s [asb] exited: %v\n", time.Now().Format(time.RFC3339), exitReason(err))
+
+		if p.config.Restart == RestartNever || (p.config.Restart == RestartOnFailure && err == nil) {
+			return
+		}
+		if time.Since(started) >= programStable {
+			backoff = programBackoffMin
+		}
+		log.Printf("Program %s exited (%v), restarting in %s", p.config.Name, exitReason(err), backoff)
+
+		select {
+		case <-stop:
+			return
+		case <-time.After(backoff):
+		}
+		s.watchdog.RecordRestart()
+		backoff = min(backoff*2, programBackoffMax)
+	}
+}
+
+// startFailed marks a program that couldn't be started and reports it
+func (s *Supervisor) startFailed(p *program, launched chan<- error, err error) {
+	log.Printf("Program %s not started: %v", p.config.Name, err)
+	s.mu.Lock()
+	p.failed = true
+	s.mu.Unlock()
+	launched <- err
+}
+
+// terminate sends SIGTERM to the process group and SIGKILL once timeout
+// passes, then waits for the process to be reaped
+func terminate(pid int, timeout time.Duration, exited <-chan error) {
+	syscall.Kill(-pid, syscall.SIGTERM)
+	select {
+	case <-exited:
+		return
+	case <-time.After(timeout):
+	}
+
+	syscall.Kill(-pid, syscall.SIGKILL)
+	// Wait gives up on the output after WaitDelay; this only guards against
+	// a process that can't be reaped at all
+	select {
+	case <-exited:
+	case <-time.After(2 * programWaitDelay):
+		log.Printf("Process %d didn't exit after SIGKILL", pid)
+	}
+}
+
+// exitReason describes how a program ended
+func exitReason(err error) string {
+	if err == nil {
+		return "exit status 0"
+	}
+	return err.Error()
+}
@@ -0,0 +1,121 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"android-server-brain/config"
+	"android-server-brain/internal/i18n"
+	"android-server-brain/internal/transport/memory"
+)
+
+// newTestSupervisor creates a supervisor for the given programs with its
+// state and logs in a temporary directory
+func newTestSupervisor(t *testing.T, programs ...config.ProgramConfig) (*Supervisor, *Watchdog) {
+	t.Helper()
+	cfg := &config.Config{StorageDir: t.TempDir(), Programs: programs}
+	watchdog := NewWatchdog(memory.New(), cfg, i18n.NewStore(""), time.Minute)
+	s := NewSupervisor(cfg, watchdog)
+	t.Cleanup(s.Shutdown)
+	return s, watchdog
+}
+
+func TestSupervisorCountsRestarts(t *testing.T) {
+	s, watchdog := newTestSupervisor(t, config.ProgramConfig{Name: "crash", Command: "exit 1", Restart: RestartAlways})
+	s.StartAll()
+
+	// The first restart follows after the minimum backoff
+	deadline := time.Now().Add(programBackoffMin + 2*time.Second)
+	for {
+		stats := watchdog.Stats(time.Now(), time.Now().AddDate(0, 0, 1))
+		if stats.Restarts > 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("supervisor restart not counted in the day stats")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func TestSupervisorStopWithDetachedChild(t *testing.T) {
+	// The detached sleep leaves the process group but keeps the log pipe open
+	s, _ := newTestSupervisor(t, config.ProgramConfig{
+		Name:               "detach",
+		Command:            "setsid sleep 5 & exec sleep 5",
+		StopTimeoutSeconds: 1,
+	})
+	if _, err := s.Start(context.Background(), "detach"); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(200 * time.Millisecond) // let sh fork the detached child
+
+	stopped := make(chan error, 1)
+	go func() {
+		_, err := s.Stop(context.Background(), "detach")
+		stopped <- err
+	}()
+	select {
+	case err := <-stopped:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(programWaitDelay + 2*time.Second):
+		t.Fatal("Stop hangs on the detached child")
+	}
+	if status, _ := s.Status(context.Background(), "detach"); status.State != ServiceDown {
+		t.Errorf("got state %v after stop", status.State)
+	}
+}
+
+func TestSupervisorStartFailure(t *testing.T) {
+	blocker := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(blocker, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	s, _ := newTestSupervisor(t,
+		config.ProgramConfig{Name: "nodir", Command: "true", Dir: filepath.Join(blocker, "missing")},
+		config.ProgramConfig{Name: "nolog", Command: "true", LogFile: filepath.Join(blocker, "app.log")},
+	)
+
+	for _, name := range []string{"nodir", "nolog"} {
+		if _, err := s.Start(context.Background(), name); err == nil {
+			t.Errorf("%s: want a start error", name)
+		}
+		// The run loop has ended once it's reported as failed
+		status, _ := s.Status(context.Background(), name)
+		if status.State != ServiceFailed {
+			t.Errorf("%s: got state %v, want failed", name, status.State)
+		}
+	}
+}
+
+func TestSupervisorResults(t *testing.T) {
+	s, _ := newTestSupervisor(t, config.ProgramConfig{Name: "app", Command: "sleep 5", StopTimeoutSeconds: 1})
+	ctx := context.Background()
+
+	steps := []struct {
+		action func(context.Context, string) (string, error)
+		err    error
+	}{
+		{s.Start, nil},
+		{s.Start, ErrAlreadyRunning},
+		{s.Restart, nil},
+		{s.Stop, nil},
+		{s.Stop, ErrNotRunning},
+		{s.Restart, nil},
+		{s.Disable, nil},
+		{s.Enable, nil},
+	}
+	for i, step := range steps {
+		// Messages are rendered by the caller in the user's language
+		output, err := step.action(ctx, "app")
+		if output != "" || !errors.Is(err, step.err) {
+			t.Errorf("step %d: got %q, %v, want %v", i, output, err, step.err)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package system
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"android-server-brain/config"
+)
+
+// TempLevel is the severity of the battery temperature
+type TempLevel int
+
+const (
+	TempNormal TempLevel = iota
+	TempWarning
+	TempCritical
+)
+
+func (l TempLevel) String() string {
+	switch l {
+	case TempWarning:
+		return "warning"
+	case TempCritical:
+		return "critical"
+	default:
+		return "normal"
+	}
+}
+
+// checkTemperature alerts when the battery gets hot, repeats the alert
+// after the cooldown while it stays hot and runs the configured actions
+func (c *BatteryCheck) checkTemperature(ctx context.Context, battery *BatteryStatus) []Alert {
+	t := c.config
+	previous := c.tempLevel
+	level := evaluateTempLevel(t, previous, battery.Temperature)
+	c.tempLevel = level
+
+	cooldown := time.Duration(t.TempCooldown) * time.Minute
+	switch {
+	case level > previous:
+		// Getting hotter: alert immediately
+	case level == TempNormal && previous != TempNormal:
+		// Cooled down: report recovery once
+	case level != TempNormal && time.Since(c.lastTempAlert) >= cooldown:
+		// Still hot: remind after the cooldown
+	default:
+		return nil
+	}
+
+	severity := StatusOK
+	switch level {
+	case TempWarning:
+		severity = StatusWarning
+	case TempCritical:
+		severity = StatusCritical
+	}
+	alerts := []Alert{batteryAlert(severity, "watchdog.temp_"+level.String(), battery)}
+	c.lastTempAlert = time.Now()
+
+	// Run the overheat actions on entering the critical level and the
+	// cooldown actions once the battery is back to normal. Hysteresis makes
+	// a cooling battery pass through the warning level, so the flag rather
+	// than the previous level tells whether the overheat actions are active.
+	if level == TempCritical && !c.overheated {
+		c.overheated = true
+		alerts = append(alerts, runActions(ctx, "overheat", t.OverheatCommands)...)
+	} else if level == TempNormal && c.overheated {
+		c.overheated = false
+		alerts = append(alerts, runActions(ctx, "cooldown", t.CooldownCommands)...)
+	}
+	return alerts
+}
+
+// evaluateTempLevel maps a temperature to a level with hysteresis on the way down
+func evaluateTempLevel(t config.BatteryConfig, current TempLevel, temperature float64) TempLevel {
+	level := TempNormal
+	switch {
+	case temperature >= t.TempCritical:
+		level = TempCritical
+	case temperature >= t.TempWarning:
+		level = TempWarning
+	}
+
+	if level >= current {
+		return level
+	}
+
+	for current > level {
+		threshold := t.TempWarning
+		if current == TempCritical {
+			threshold = t.TempCritical
+		}
+		if temperature > threshold-t.TempHysteresis {
+			break
+		}
+		current--
+	}
+	return current
+}
+
+// runActions executes automated reactions within the check's deadline and
+// returns a report of their outcome
+func runActions(ctx context.Context, kind string, commands []string) []Alert {
+	if len(commands) == 0 {
+		return nil
+	}
+
+	var report strings.Builder
+	for _, command := range commands {
+		output, err := ExecuteCommandContext(ctx, command)
+		status := "✅"
+		if err != nil {
+			status = "❌"
+			log.Printf("Watchdog %s action %q failed: %v", kind, command, err)
+		}
+		fmt.Fprintf(&report, "%s `%s`\n%s\n", status, command, strings.TrimSpace(output))
+	}
+
+	return []Alert{{
+		Severity: StatusWarning,
+		Message:  Msg("watchdog.actions_"+kind, report.String()),
+	}}
+}
+
+// checkHealth alerts when the battery health leaves GOOD and when it recovers
+func (c *BatteryCheck) checkHealth(battery *BatteryStatus) []Alert {
+	health := battery.Health
+	if health == "" || health == c.lastHealth {
+		return nil
+	}
+
+	previous := c.lastHealth
+	c.lastHealth = health
+
+	switch {
+	case health != "GOOD":
+		return []Alert{{
+			Severity: StatusWarning,
+			Message:  Msg("watchdog.health_bad", health, battery.Temperature, battery.Percentage),
+		}}
+	case previous != "":
+		return []Alert{{
+			Severity: StatusOK,
+			Message:  Msg("watchdog.health_good", previous),
+		}}
+	}
+
+	// First reading and healthy: nothing to report
+	return nil
+}
@@ -0,0 +1,118 @@
+package system
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"android-server-brain/config"
+)
+
+// newThermalCheck returns a battery check reading temperatures from *temp
+func newThermalCheck(temp *float64) *BatteryCheck {
+	c := NewBatteryCheck(config.BatteryConfig{
+		TempWarning:      40,
+		TempCritical:     45,
+		TempHysteresis:   2,
+		TempCooldown:     30,
+		OverheatCommands: []string{"echo overheat"},
+		CooldownCommands: []string{"echo cooldown"},
+	}, time.Minute)
+	c.read = func() (*BatteryStatus, error) {
+		return &BatteryStatus{Health: "GOOD", Percentage: 80, Status: "DISCHARGING", Temperature: *temp}, nil
+	}
+	return c
+}
+
+// actionKeys returns the keys of the action reports among alerts
+func actionKeys(alerts []Alert) []string {
+	var keys []string
+	for _, a := range alerts {
+		if a.Message.Key == "watchdog.actions_overheat" || a.Message.Key == "watchdog.actions_cooldown" {
+			keys = append(keys, a.Message.Key)
+		}
+	}
+	return keys
+}
+
+func TestThermalActions(t *testing.T) {
+	tests := []struct {
+		name  string
+		temps []float64
+		want  [][]string // action reports after each reading
+	}{
+		{
+			name:  "cooling through warning",
+			temps: []float64{46, 44, 42, 37},
+			want:  [][]string{{"watchdog.actions_overheat"}, nil, nil, {"watchdog.actions_cooldown"}},
+		},
+		{
+			name:  "straight back to normal",
+			temps: []float64{46, 30},
+			want:  [][]string{{"watchdog.actions_overheat"}, {"watchdog.actions_cooldown"}},
+		},
+		{
+			name:  "critical again before cooling down",
+			temps: []float64{46, 42, 46, 30},
+			want:  [][]string{{"watchdog.actions_overheat"}, nil, nil, {"watchdog.actions_cooldown"}},
+		},
+		{
+			name:  "warning only",
+			temps: []float64{41, 44, 30},
+			want:  [][]string{nil, nil, nil},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var temp float64
+			c := newThermalCheck(&temp)
+			for i := range tt.temps {
+				temp = tt.temps[i]
+				got := actionKeys(c.Run(context.Background()).Alerts)
+				if len(got) != len(tt.want[i]) || len(got) > 0 && got[0] != tt.want[i][0] {
+					t.Errorf("at %.0f°C: actions %v, want %v", temp, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestThermalActionsSurviveRestart(t *testing.T) {
+	temp := 46.0
+	c := newThermalCheck(&temp)
+	c.Run(context.Background())
+	temp = 42
+	c.Run(context.Background())
+
+	data, err := json.Marshal(c.SaveState())
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored := newThermalCheck(&temp)
+	if err := restored.RestoreState(data); err != nil {
+		t.Fatal(err)
+	}
+
+	temp = 37
+	if got := actionKeys(restored.Run(context.Background()).Alerts); len(got) != 1 || got[0] != "watchdog.actions_cooldown" {
+		t.Errorf("after restart: actions %v, want the cooldown actions", got)
+	}
+}
+
+func TestThermalActionsFollowCheckDeadline(t *testing.T) {
+	temp := 46.0
+	c := newThermalCheck(&temp)
+	c.config.OverheatCommands = []string{"sleep 10"}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if got := actionKeys(c.Run(ctx).Alerts); len(got) != 1 {
+		t.Fatalf("got %v, want the overheat report", got)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("overheat action ran for %s past the check deadline", elapsed)
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"os/exec"
 	"path/filepath"
 	"time"
+
+	"android-server-brain/internal/i18n"
 )
 
 // UpdateResult represents the result of an update operation
@@ -20,7 +22,7 @@ type UpdateResult struct {
 }
 
 // CheckForUpdates checks if there are updates available from the git repository
-func CheckForUpdates() (*UpdateResult, error) {
+func CheckForUpdates(lang i18n.Lang) (*UpdateResult, error) {
 	result := &UpdateResult{}
 
 	// Get current working directory
@@ -28,7 +30,7 @@ func CheckForUpdates() (*UpdateResult, error) {
 	if err != nil {
 		result.Success = false
 		result.Error = err
-		result.Message = fmt.Sprintf("❌ Failed to get working directory: %v", err)
+		result.Message = i18n.T(lang, "update.no_workdir", err)
 		return result, nil
 	}
 
@@ -36,7 +38,7 @@ func CheckForUpdates() (*UpdateResult, error) {
 	gitDir := filepath.Join(wd, ".git")
 	if _, err := os.Stat(gitDir); os.IsNotExist(err) {
 		result.Success = false
-		result.Message = "❌ Not a git repository. Cannot check for updates."
+		result.Message = i18n.T(lang, "update.not_git")
 		return result, nil
 	}
 
@@ -49,7 +51,7 @@ func CheckForUpdates() (*UpdateResult, error) {
 	if err != nil {
 		result.Success = false
 		result.Error = err
-		result.Message = fmt.Sprintf("❌ Failed to fetch updates: %v\nOutput: %s", err, string(fetchOutput))
+		result.Message = i18n.T(lang, "update.fetch_failed", err, string(fetchOutput))
 		return result, nil
 	}
 
@@ -59,14 +61,14 @@ func CheckForUpdates() (*UpdateResult, error) {
 	if err != nil {
 		result.Success = false
 		result.Error = err
-		result.Message = fmt.Sprintf("❌ Failed to check status: %v\nOutput: %s", err, string(statusOutput))
+		result.Message = i18n.T(lang, "update.status_failed", err, string(statusOutput))
 		return result, nil
 	}
 
 	statusStr := string(statusOutput)
 	if contains(statusStr, "Your branch is behind") || contains(statusStr, "can be fast-forwarded") {
 		result.Success = true
-		result.Message = "✅ Updates are available!"
+		result.Message = i18n.T(lang, "update.available")
 
 		// Get current version/commit
 		currentCmd := exec.CommandContext(ctx, "git", "rev-parse", "--short", "HEAD")
@@ -82,12 +84,12 @@ func CheckForUpdates() (*UpdateResult, error) {
 	}
 
 	result.Success = true
-	result.Message = "✅ Already up to date. No updates available."
+	result.Message = i18n.T(lang, "update.up_to_date")
 	return result, nil
 }
 
 // PerformUpdate performs the actual update process with backup
-func PerformUpdate() (*UpdateResult, error) {
+func PerformUpdate(lang i18n.Lang) (*UpdateResult, error) {
 	result := &UpdateResult{}
 
 	// Get current working directory
@@ -95,7 +97,7 @@ func PerformUpdate() (*UpdateResult, error) {
 	if err != nil {
 		result.Success = false
 		result.Error = err
-		result.Message = fmt.Sprintf("❌ Failed to get working directory: %v", err)
+		result.Message = i18n.T(lang, "update.no_workdir", err)
 		return result, nil
 	}
 
@@ -105,7 +107,7 @@ func PerformUpdate() (*UpdateResult, error) {
 	if err != nil {
 		result.Success = false
 		result.Error = err
-		result.Message = fmt.Sprintf("❌ Failed to create backup: %v", err)
+		result.Message = i18n.T(lang, "update.backup_failed", err)
 		return result, nil
 	}
 	result.BackupPath = backupPath
@@ -119,7 +121,7 @@ func PerformUpdate() (*UpdateResult, error) {
 	if err != nil {
 		result.Success = false
 		result.Error = err
-		result.Message = fmt.Sprintf("❌ Update failed: %v\nOutput: %s", err, string(pullOutput))
+		result.Message = i18n.T(lang, "update.pull_failed", err, string(pullOutput))
 
 		// Attempt rollback
 		rollbackCmd := exec.Command("cp", "-r", backupPath+"/*", wd+"/")
@@ -134,12 +136,12 @@ func PerformUpdate() (*UpdateResult, error) {
 	result.NewVersion = string(currentOutput)
 
 	result.Success = true
-	result.Message = fmt.Sprintf("✅ Successfully updated!\nOutput: %s", string(pullOutput))
+	result.Message = i18n.T(lang, "update.success", string(pullOutput))
 	return result, nil
 }
 
 // RestartASB restarts the ASB service after update
-func RestartASB() (string, error) {
+func RestartASB(lang i18n.Lang) (string, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
@@ -148,7 +150,7 @@ func RestartASB() (string, error) {
 	output, err := cmd.CombinedOutput()
 
 	if err != nil {
-		return fmt.Sprintf("⚠️ Could not terminate old ASB process: %v\nOutput: %s", err, string(output)), err
+		return i18n.T(lang, "update.kill_failed", err, string(output)), err
 	}
 
 	// Start new ASB process
@@ -157,10 +159,10 @@ func RestartASB() (string, error) {
 
 	err = startCmd.Start()
 	if err != nil {
-		return fmt.Sprintf("❌ Failed to start new ASB process: %v", err), err
+		return i18n.T(lang, "update.start_failed", err), err
 	}
 
-	return "✅ ASB restarted successfully with new version!", nil
+	return i18n.T(lang, "update.restarted"), nil
 }
 
 // CleanupBackup removes backup files/directories
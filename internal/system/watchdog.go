@@ -1,15 +1,19 @@
 package system
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
 	"os/exec"
+	"strings"
+	"sync"
+	"text/tabwriter"
 	"time"
 
 	"android-server-brain/config"
-
-	tele "gopkg.in/telebot.v3"
+	"android-server-brain/internal/i18n"
+	"android-server-brain/internal/transport"
 )
 
 // BatteryStatus represents the battery information from termux-api
@@ -21,79 +25,476 @@ type BatteryStatus struct {
 	Temperature float64 `json:"temperature"`
 }
 
-// Watchdog manages periodic system monitoring
+// Watchdog schedules health checks and delivers their alerts
 type Watchdog struct {
-	bot          *tele.Bot
-	config       *config.Config
-	interval     time.Duration
-	lastNotified bool
-	startTime    time.Time
+	router    *AlertRouter
+	langs     *i18n.Store
+	config    *config.Config
+	interval  time.Duration // default interval for checks without their own
+	startTime time.Time
+
+	mu       sync.Mutex
+	ctx      context.Context // cancelled by Stop, parent of check runs
+	cancel   context.CancelFunc
+	running  sync.WaitGroup // monitoring loop and check runs
+	stopped  bool           // set by Stop, no more check runs are started
+	paused   bool
+	checks   []*checkState
+	mutes    map[string]time.Time // check name (or MuteAll) -> muted until
+	history  []AlertRecord
+	stats    []*DayStats              // daily statistics for the digest, oldest first
+	charging int                      // last charger state: -1 unknown, 0 unplugged, 1 charging
+	restored map[string]checkSnapshot // persisted check state, applied in AddCheck
+	wake     chan struct{}
+
+	statePath string
+	saveMu    sync.Mutex
 }
 
-// NewWatchdog creates a new watchdog instance
-func NewWatchdog(bot *tele.Bot, cfg *config.Config, interval time.Duration) *Watchdog {
-	return &Watchdog{
-		bot:          bot,
-		config:       cfg,
-		interval:     interval,
-		lastNotified: false,
-		startTime:    time.Now(),
+// Inline buttons attached to every alert; the button data is the check name
+const (
+	ButtonSnooze = "alert_snooze"
+	ButtonMute   = "alert_mute"
+)
+
+// MuteAll mutes every check
+const MuteAll = "all"
+
+// SnoozeDuration is how long the snooze button silences a check
+const SnoozeDuration = time.Hour
+
+// NewWatchdog creates a new watchdog instance with the built-in battery and
+// disk checks and one check per configured process and endpoint
+func NewWatchdog(notifier transport.Notifier, cfg *config.Config, langs *i18n.Store, interval time.Duration) *Watchdog {
+	w := &Watchdog{
+		router:    NewAlertRouter(notifier, cfg, langs),
+		langs:     langs,
+		config:    cfg,
+		interval:  interval,
+		startTime: time.Now(),
+		ctx:       context.Background(),
+		charging:  -1,
+		mutes:     make(map[string]time.Time),
+		wake:      make(chan struct{}, 1),
+		statePath: cfg.StatePath("watchdog.json"),
+	}
+	w.load()
+
+	w.AddCheck(NewBatteryCheck(cfg.Battery, 0))
+	w.AddCheck(NewDiskCheck(cfg.Disk))
+	for _, process := range cfg.Processes {
+		w.AddCheck(NewProcessCheck(process))
 	}
+	for _, endpoint := range cfg.Endpoints {
+		w.AddCheck(NewEndpointCheck(endpoint))
+	}
+	return w
+}
+
+// AddCheck registers a check; its first run is one interval from now, or
+// one interval after its last run before a restart
+func (w *Watchdog) AddCheck(c Check) {
+	w.mu.Lock()
+	interval := c.Interval()
+	if interval <= 0 {
+		interval = w.interval
+	}
+	w.mu.Unlock()
+
+	st := &checkState{
+		check:       c,
+		interval:    interval,
+		nextRun:     time.Now().Add(interval),
+		usesDefault: c.Interval() <= 0,
+	}
+
+	w.mu.Lock()
+	w.restore(st)
+	w.checks = append(w.checks, st)
+	w.mu.Unlock()
+
+	w.poke()
 }
 
-// Start begins the watchdog monitoring loop
-func (w *Watchdog) Start() {
+// Start begins the watchdog monitoring loop. It runs until ctx is
+// cancelled or Stop is called.
+func (w *Watchdog) Start(ctx context.Context) {
+	w.mu.Lock()
+	w.ctx, w.cancel = context.WithCancel(ctx)
+	ctx = w.ctx
+	interval := w.interval
+	w.mu.Unlock()
+
+	w.running.Add(1)
 	go func() {
-		ticker := time.NewTicker(w.interval)
-		defer ticker.Stop()
+		defer w.running.Done()
+		log.Printf("Watchdog started with interval: %v", interval)
 
-		log.Printf("Watchdog started with interval: %v", w.interval)
+		timer := time.NewTimer(w.untilNextRun())
+		defer timer.Stop()
 
-		for range ticker.C {
-			w.checkBattery()
+		for {
+			select {
+			case <-ctx.Done():
+				log.Printf("Watchdog stopped")
+				return
+			case <-timer.C:
+				w.runDue()
+			case <-w.wake:
+				if !timer.Stop() {
+					select {
+					case <-timer.C:
+					default:
+					}
+				}
+			}
+			timer.Reset(w.untilNextRun())
 		}
 	}()
 }
 
-// checkBattery monitors battery status and sends notifications when needed
-func (w *Watchdog) checkBattery() {
-	battery, err := getBatteryStatus()
-	if err != nil {
-		log.Printf("Failed to get battery status: %v", err)
+// Stop cancels the monitoring loop and running checks, waits for them to
+// finish and saves the state
+func (w *Watchdog) Stop() {
+	w.mu.Lock()
+	w.stopped = true
+	cancel := w.cancel
+	w.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+	w.running.Wait()
+	w.router.Stop()
+	w.save()
+}
+
+// StartTime returns when the watchdog started, kept across MarkRestart restarts
+func (w *Watchdog) StartTime() time.Time {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.startTime
+}
+
+// Pause stops scheduling checks until Resume; RunNow still works
+func (w *Watchdog) Pause() {
+	w.mu.Lock()
+	w.paused = true
+	w.mu.Unlock()
+	log.Printf("Watchdog paused")
+}
+
+// Resume continues scheduling; checks that became due meanwhile run right away
+func (w *Watchdog) Resume() {
+	w.mu.Lock()
+	w.paused = false
+	w.mu.Unlock()
+	log.Printf("Watchdog resumed")
+	w.poke()
+}
+
+// Paused reports whether scheduling is paused
+func (w *Watchdog) Paused() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.paused
+}
+
+// Interval returns the default check interval
+func (w *Watchdog) Interval() time.Duration {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.interval
+}
+
+// SetInterval changes the default interval and reschedules the checks using it
+func (w *Watchdog) SetInterval(d time.Duration) {
+	w.mu.Lock()
+	w.interval = d
+	now := time.Now()
+	for _, st := range w.checks {
+		if !st.usesDefault {
+			continue
+		}
+		st.interval = d
+		base := st.lastRun
+		if base.IsZero() {
+			base = now
+		}
+		st.nextRun = base.Add(d)
+	}
+	w.mu.Unlock()
+
+	log.Printf("Watchdog interval set to %v", d)
+	w.poke()
+}
+
+// RunNow runs the named check, or every check for an empty name, right away
+// and waits for the runs to finish. It returns the number of checks run;
+// checks that are already running are skipped.
+func (w *Watchdog) RunNow(name string) int {
+	var done sync.WaitGroup
+	w.mu.Lock()
+	var due []*checkState
+	for _, st := range w.checks {
+		if !st.running && (name == "" || st.check.Name() == name) {
+			due = append(due, st)
+		}
+	}
+	n := w.launch(due, &done)
+	w.mu.Unlock()
+
+	done.Wait()
+	return n
+}
+
+// launch marks the given checks running and runs them concurrently. The
+// caller holds w.mu; nothing is started once the watchdog is stopped, so
+// Stop's wait isn't raced by new runs. It returns the number started.
+func (w *Watchdog) launch(due []*checkState, done *sync.WaitGroup) int {
+	if w.stopped {
+		return 0
+	}
+	for _, st := range due {
+		st.running = true
+		done.Add(1)
+		w.running.Add(1)
+		go func(st *checkState, ctx context.Context, timeout time.Duration) {
+			defer done.Done()
+			defer w.running.Done()
+			w.runCheck(ctx, st, timeout)
+		}(st, w.ctx, st.interval)
+	}
+	return len(due)
+}
+
+// poke makes the scheduler re-evaluate the next run time
+func (w *Watchdog) poke() {
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// untilNextRun returns the time until the earliest scheduled check
+func (w *Watchdog) untilNextRun() time.Duration {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	next := time.Now().Add(w.interval)
+	if w.paused {
+		return w.interval
+	}
+	for _, st := range w.checks {
+		if !st.running && st.nextRun.Before(next) {
+			next = st.nextRun
+		}
+	}
+	if d := time.Until(next); d > 0 {
+		return d
+	}
+	return 0
+}
+
+// runDue starts every check whose next run time has passed. Checks run
+// concurrently so a slow one doesn't delay the others.
+func (w *Watchdog) runDue() {
+	now := time.Now()
+
+	w.mu.Lock()
+	if w.paused {
+		w.mu.Unlock()
+		return
+	}
+	var due []*checkState
+	for _, st := range w.checks {
+		if !st.running && !st.nextRun.After(now) {
+			due = append(due, st)
+		}
+	}
+	w.launch(due, &sync.WaitGroup{})
+	w.mu.Unlock()
+}
+
+// runCheck runs a single check, limited to timeout, and delivers its
+// alerts. A run interrupted by Stop is discarded.
+func (w *Watchdog) runCheck(parent context.Context, st *checkState, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	result := st.check.Run(ctx)
+	cancel()
+
+	if parent.Err() != nil {
+		w.mu.Lock()
+		st.running = false
+		w.mu.Unlock()
 		return
 	}
+	state := saveState(st.check)
+
+	alerted := w.deliver(st.check.Name(), result.Alerts)
+
+	w.mu.Lock()
+	st.running = false
+	st.lastRun = time.Now()
+	st.nextRun = st.lastRun.Add(st.interval)
+	st.lastResult = result
+	st.state = state
+	w.recordStats(st.check.Name(), result)
+	if alerted {
+		st.lastAlert = st.lastRun
+	}
+	w.mu.Unlock()
+
+	w.save()
+	w.poke()
+}
+
+// deliver routes alerts to their sinks and reports whether any was delivered.
+// Alerts of muted checks and during maintenance windows are only logged.
+func (w *Watchdog) deliver(check string, alerts []Alert) bool {
+	if len(alerts) == 0 {
+		return false
+	}
+
+	now := time.Now()
+	lang := w.langs.Get(w.config.AdminID, "")
+	reason, suppressed := w.suppressed(check, now)
+
+	records := make([]AlertRecord, len(alerts))
+	for i, alert := range alerts {
+		records[i] = AlertRecord{
+			Time:       now,
+			Check:      check,
+			Severity:   alert.Severity,
+			Key:        alert.Message.Key,
+			Text:       alert.Message.Render(lang),
+			Suppressed: suppressed,
+		}
+	}
+	w.record(records...)
+
+	if suppressed {
+		for _, alert := range alerts {
+			log.Printf("Suppressed %s alert (%s): %s", check, reason, alert.Message.Key)
+		}
+		return false
+	}
+
+	return w.router.Route(check, alerts)
+}
+
+// Acknowledge cancels the escalation of a delivered alert
+func (w *Watchdog) Acknowledge(id string) bool {
+	return w.router.Acknowledge(id)
+}
+
+// suppressed reports whether alerts of a check are silenced and why
+func (w *Watchdog) suppressed(check string, now time.Time) (string, bool) {
+	if until, ok := w.MutedUntil(check); ok {
+		return fmt.Sprintf("muted until %s", until.Format("2006-01-02 15:04")), true
+	}
+	for i := range w.config.Maintenance {
+		if w.config.Maintenance[i].Active(check, now) {
+			return "maintenance window", true
+		}
+	}
+	return "", false
+}
+
+// HasCheck reports whether a check with the given name is registered
+func (w *Watchdog) HasCheck(name string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, st := range w.checks {
+		if st.check.Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Mute silences alerts of a check, or of all checks with MuteAll, until the given time
+func (w *Watchdog) Mute(check string, until time.Time) {
+	w.mu.Lock()
+	w.mutes[check] = until
+	w.mu.Unlock()
+	w.save()
+	log.Printf("Muted %s alerts until %s", check, until.Format("2006-01-02 15:04"))
+}
+
+// Unmute lifts a mute and reports whether there was one
+func (w *Watchdog) Unmute(check string) bool {
+	w.mu.Lock()
+	_, ok := w.mutes[check]
+	delete(w.mutes, check)
+	w.mu.Unlock()
 
-	// Check if battery is low and not charging
-	isLowBattery := battery.Percentage < 20
-	isCharging := battery.Plugged == "PLUGGED_AC" || battery.Plugged == "PLUGGED_USB" || battery.Status == "CHARGING"
-
-	// Send notification only if:
-	// 1. Battery is low (<20%)
-	// 2. Device is not charging
-	// 3. We haven't notified recently (anti-spam)
-	if isLowBattery && !isCharging && !w.lastNotified {
-		message := fmt.Sprintf(
-			"⚠️ *Low Battery Alert*\n\n"+
-				"🔋 Current charge: %.1f%%\n"+
-				"🌡 Temperature: %.1f°C\n"+
-				"🔌 Status: %s\n\n"+
-				"Please connect charger!",
-			battery.Percentage,
-			battery.Temperature,
-			battery.Status,
-		)
-
-		_, err := w.bot.Send(&tele.User{ID: w.config.AdminID}, message, tele.ModeMarkdown)
-		if err != nil {
-			log.Printf("Failed to send battery alert: %v", err)
+	if ok {
+		w.save()
+	}
+	return ok
+}
+
+// MutedUntil returns when the mute of a check ends, taking MuteAll into account
+func (w *Watchdog) MutedUntil(check string) (time.Time, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := time.Now()
+	var until time.Time
+	for _, name := range []string{check, MuteAll} {
+		if t, ok := w.mutes[name]; ok {
+			if !t.After(now) {
+				delete(w.mutes, name)
+			} else if t.After(until) {
+				until = t
+			}
+		}
+	}
+	return until, !until.IsZero()
+}
+
+// Mutes returns the active mutes by check name
+func (w *Watchdog) Mutes() map[string]time.Time {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := time.Now()
+	active := make(map[string]time.Time, len(w.mutes))
+	for name, until := range w.mutes {
+		if until.After(now) {
+			active[name] = until
 		} else {
-			log.Printf("Sent low battery alert: %.1f%%", battery.Percentage)
-			w.lastNotified = true
+			delete(w.mutes, name)
 		}
-	} else if !isLowBattery || isCharging {
-		// Reset notification flag when battery is healthy or charging
-		w.lastNotified = false
 	}
+	return active
+}
+
+// Checks returns a snapshot of all registered checks
+func (w *Watchdog) Checks() []CheckInfo {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	infos := make([]CheckInfo, 0, len(w.checks))
+	for _, st := range w.checks {
+		infos = append(infos, CheckInfo{
+			Name:      st.check.Name(),
+			Interval:  st.interval,
+			Status:    st.lastResult.Status,
+			Summary:   st.lastResult.Summary,
+			LastRun:   st.lastRun,
+			LastAlert: st.lastAlert,
+			NextRun:   st.nextRun,
+		})
+	}
+	return infos
+}
+
+// IsCharging reports whether the device is connected to a power source
+func (b *BatteryStatus) IsCharging() bool {
+	return b.Plugged == "PLUGGED_AC" || b.Plugged == "PLUGGED_USB" || b.Plugged == "PLUGGED_WIRELESS" || b.Status == "CHARGING"
 }
 
 // getBatteryStatus retrieves battery information from termux-api
@@ -113,35 +514,82 @@ func getBatteryStatus() (*BatteryStatus, error) {
 	return &battery, nil
 }
 
-// GetWatchdogStatus returns formatted watchdog status information
-func (w *Watchdog) GetStatus() string {
-	uptime := time.Since(w.startTime)
+// GetStatus returns formatted watchdog status with one entry per check
+func (w *Watchdog) GetStatus(lang i18n.Lang) string {
+	uptime := time.Since(w.StartTime())
 
-	return fmt.Sprintf(
-		"🐕 *Watchdog Status*\n\n"+
-			"⏱ Uptime: %v\n"+
-			"📅 Interval: %v\n"+
-			"📢 Last notified: %v",
+	var b strings.Builder
+	b.WriteString(i18n.T(lang, "watchdog.status",
 		uptime.Round(time.Second),
-		w.interval,
-		w.lastNotified,
-	)
+		w.Interval(),
+	))
+	if w.Paused() {
+		b.WriteString(i18n.T(lang, "watchdog.paused_line"))
+	}
+
+	for _, info := range w.Checks() {
+		summary := info.Summary.Render(lang)
+		if summary == "" {
+			summary = i18n.T(lang, "check.pending")
+		}
+		b.WriteString(i18n.T(lang, "watchdog.check_line",
+			info.Status.Icon(),
+			info.Name,
+			summary,
+			formatAgo(lang, info.LastRun),
+			formatAgo(lang, info.LastAlert),
+			formatIn(lang, info.NextRun),
+		))
+	}
+	return b.String()
+}
+
+// GetChecksTable returns all checks as a compact monospace table
+func (w *Watchdog) GetChecksTable(lang i18n.Lang) string {
+	var table strings.Builder
+	tw := tabwriter.NewWriter(&table, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, i18n.T(lang, "checks.header"))
+	for _, info := range w.Checks() {
+		summary := info.Summary.Render(lang)
+		if summary == "" {
+			summary = i18n.T(lang, "check.pending")
+		}
+		lastRun := "-"
+		if !info.LastRun.IsZero() {
+			lastRun = time.Since(info.LastRun).Round(time.Second).String()
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", info.Name, info.Status, lastRun, strings.ReplaceAll(summary, "`", "'"))
+	}
+	tw.Flush()
+
+	return i18n.T(lang, "checks.title", table.String())
+}
+
+// formatAgo renders a past timestamp relative to now
+func formatAgo(lang i18n.Lang, t time.Time) string {
+	if t.IsZero() {
+		return i18n.T(lang, "time.never")
+	}
+	return i18n.T(lang, "time.ago", time.Since(t).Round(time.Second))
+}
+
+// formatIn renders a future timestamp relative to now
+func formatIn(lang i18n.Lang, t time.Time) string {
+	d := time.Until(t).Round(time.Second)
+	if d <= 0 {
+		return i18n.T(lang, "time.now")
+	}
+	return i18n.T(lang, "time.in", d)
 }
 
 // GetBatteryInfo returns formatted battery information for manual checks
-func GetBatteryInfo() string {
+func GetBatteryInfo(lang i18n.Lang) string {
 	battery, err := getBatteryStatus()
 	if err != nil {
-		return fmt.Sprintf("❌ Battery info unavailable: %v", err)
+		return i18n.T(lang, "battery.unavailable", err)
 	}
 
-	return fmt.Sprintf(
-		"🔋 *Battery Status*\n\n"+
-			"Charge: %.1f%%\n"+
-			"Status: %s\n"+
-			"Plugged: %s\n"+
-			"Health: %s\n"+
-			"Temperature: %.1f°C",
+	return i18n.T(lang, "battery.report",
 		battery.Percentage,
 		battery.Status,
 		battery.Plugged,
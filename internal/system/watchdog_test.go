@@ -0,0 +1,73 @@
+package system
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"android-server-brain/config"
+	"android-server-brain/internal/i18n"
+	"android-server-brain/internal/transport/memory"
+)
+
+// defaultCheck uses the watchdog's default interval
+type defaultCheck struct{}
+
+func (defaultCheck) Name() string            { return "default" }
+func (defaultCheck) Interval() time.Duration { return 0 }
+func (defaultCheck) Run(ctx context.Context) CheckResult {
+	time.Sleep(time.Millisecond)
+	return CheckResult{Status: StatusOK, Summary: Msg("check.restored")}
+}
+
+func newTestWatchdog(t *testing.T) *Watchdog {
+	t.Helper()
+	cfg := &config.Config{StorageDir: t.TempDir()}
+	w := NewWatchdog(memory.New(), cfg, i18n.NewStore(""), time.Minute)
+	w.AddCheck(defaultCheck{})
+	return w
+}
+
+// Run with -race: the interval is changed while checks are started
+func TestWatchdogSetIntervalWhileRunning(t *testing.T) {
+	w := newTestWatchdog(t)
+	w.Start(context.Background())
+	defer w.Stop()
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 20; i++ {
+			w.RunNow("default")
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 1; i <= 20; i++ {
+			w.SetInterval(time.Duration(i) * time.Minute)
+		}
+	}()
+	wg.Wait()
+}
+
+func TestWatchdogNoRunsAfterStop(t *testing.T) {
+	w := newTestWatchdog(t)
+	w.Start(context.Background())
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 50; i++ {
+			w.RunNow("default")
+		}
+	}()
+	w.Stop()
+	wg.Wait()
+
+	if n := w.RunNow("default"); n != 0 {
+		t.Errorf("RunNow started %d checks after Stop", n)
+	}
+}
@@ -0,0 +1,11 @@
+package transport
+
+import "regexp"
+
+var markdownMarkers = regexp.MustCompile("(?m)^```[a-z]*\n?|```|[*`]")
+
+// StripMarkdown removes Telegram Markdown markers for plain text output,
+// like the terminal or email
+func StripMarkdown(text string) string {
+	return markdownMarkers.ReplaceAllString(text, "")
+}
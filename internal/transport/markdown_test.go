@@ -0,0 +1,17 @@
+package transport
+
+import "testing"
+
+func TestStripMarkdown(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"*Bold* and `code`", "Bold and code"},
+		{"Output:\n```\nline\n```", "Output:\nline\n"},
+		{"```bash\necho hi```", "echo hi"},
+		{"plain text", "plain text"},
+	}
+	for _, tt := range tests {
+		if got := StripMarkdown(tt.in); got != tt.want {
+			t.Errorf("StripMarkdown(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,262 @@
+package memory
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"android-server-brain/internal/transport"
+)
+
+// Message is a message recorded by the in-memory frontend
+type Message struct {
+	Ref     transport.MessageRef
+	Text    string
+	Options transport.SendOptions
+	// Response is set when the message is a callback answer
+	Response bool
+}
+
+// Frontend is an in-memory transport used for tests and local tooling.
+// It records every outgoing message and lets callers inject updates.
+type Frontend struct {
+	mu         sync.Mutex
+	handlers   map[string]transport.HandlerFunc
+	buttons    map[string]transport.HandlerFunc
+	document   transport.HandlerFunc
+	middleware []transport.MiddlewareFunc
+	files      map[string][]byte
+	messages   []Message
+	nextID     int
+	pinned     map[int64]transport.MessageRef
+}
+
+// New creates an empty in-memory frontend
+func New() *Frontend {
+	return &Frontend{
+		handlers: make(map[string]transport.HandlerFunc),
+		buttons:  make(map[string]transport.HandlerFunc),
+		files:    make(map[string][]byte),
+		pinned:   make(map[int64]transport.MessageRef),
+	}
+}
+
+// Use adds middleware applied to every handler
+func (f *Frontend) Use(mw transport.MiddlewareFunc) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.middleware = append(f.middleware, mw)
+}
+
+// Handle registers a handler for a command
+func (f *Frontend) Handle(command string, h transport.HandlerFunc) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.handlers[command] = h
+}
+
+// HandleButton registers a handler for inline buttons
+func (f *Frontend) HandleButton(unique string, h transport.HandlerFunc) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.buttons[unique] = h
+}
+
+// HandleDocument registers a handler for incoming files
+func (f *Frontend) HandleDocument(h transport.HandlerFunc) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.document = h
+}
+
+// Notify records an outgoing message
+func (f *Frontend) Notify(chatID int64, text string, opts ...transport.Option) (transport.MessageRef, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.nextID++
+	ref := transport.MessageRef{ChatID: chatID, MessageID: f.nextID}
+	f.messages = append(f.messages, Message{Ref: ref, Text: text, Options: transport.ResolveOptions(opts...)})
+	return ref, nil
+}
+
+// EditMessage replaces the text of a recorded message
+func (f *Frontend) EditMessage(ref transport.MessageRef, text string, opts ...transport.Option) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i := range f.messages {
+		if f.messages[i].Ref == ref && !f.messages[i].Response {
+			f.messages[i].Text = text
+			f.messages[i].Options = transport.ResolveOptions(opts...)
+			return nil
+		}
+	}
+	return fmt.Errorf("message %d not found in chat %d", ref.MessageID, ref.ChatID)
+}
+
+// Pin marks a message as the pinned message of its chat
+func (f *Frontend) Pin(ref transport.MessageRef) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.pinned[ref.ChatID] = ref
+	return nil
+}
+
+// Unpin removes the pin if ref is the pinned message
+func (f *Frontend) Unpin(ref transport.MessageRef) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.pinned[ref.ChatID] == ref {
+		delete(f.pinned, ref.ChatID)
+	}
+	return nil
+}
+
+// Pinned returns the pinned message of a chat
+func (f *Frontend) Pinned(chatID int64) (transport.MessageRef, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	ref, ok := f.pinned[chatID]
+	return ref, ok, nil
+}
+
+// Download writes the content registered with AddFile to localPath
+func (f *Frontend) Download(doc *transport.Document, localPath string) error {
+	f.mu.Lock()
+	content, ok := f.files[doc.FileID]
+	f.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("file %s not found", doc.FileID)
+	}
+	return os.WriteFile(localPath, content, 0644)
+}
+
+// AddFile makes content available for download under fileID
+func (f *Frontend) AddFile(fileID string, content []byte) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.files[fileID] = content
+}
+
+// Messages returns a copy of all recorded messages
+func (f *Frontend) Messages() []Message {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Message(nil), f.messages...)
+}
+
+// Last returns the most recently recorded message
+func (f *Frontend) Last() (Message, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.messages) == 0 {
+		return Message{}, false
+	}
+	return f.messages[len(f.messages)-1], true
+}
+
+// Reset drops all recorded messages
+func (f *Frontend) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.messages = nil
+}
+
+// Dispatch delivers a text message such as "/exec ls -la" from senderID
+func (f *Frontend) Dispatch(senderID int64, text string) error {
+	return f.DispatchLang(senderID, "", text)
+}
+
+// DispatchLang is like Dispatch for a client with the given language code
+func (f *Frontend) DispatchLang(senderID int64, langCode, text string) error {
+	command, payload, _ := strings.Cut(strings.TrimSpace(text), " ")
+
+	f.mu.Lock()
+	h, ok := f.handlers[command]
+	f.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("no handler for %s", command)
+	}
+
+	return f.run(h, &context{f: f, sender: senderID, chat: senderID, lang: langCode, data: strings.TrimSpace(payload)})
+}
+
+// Press simulates a press of an inline button attached to the message ref
+func (f *Frontend) Press(senderID int64, ref transport.MessageRef, unique, data string) error {
+	f.mu.Lock()
+	h, ok := f.buttons[unique]
+	f.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("no handler for button %s", unique)
+	}
+
+	return f.run(h, &context{f: f, sender: senderID, chat: ref.ChatID, data: data, callback: &ref})
+}
+
+// Upload simulates a file sent by senderID
+func (f *Frontend) Upload(senderID int64, doc *transport.Document) error {
+	f.mu.Lock()
+	h := f.document
+	f.mu.Unlock()
+	if h == nil {
+		return fmt.Errorf("no document handler")
+	}
+
+	return f.run(h, &context{f: f, sender: senderID, chat: senderID, doc: doc})
+}
+
+func (f *Frontend) run(h transport.HandlerFunc, c *context) error {
+	f.mu.Lock()
+	for i := len(f.middleware) - 1; i >= 0; i-- {
+		h = f.middleware[i](h)
+	}
+	f.mu.Unlock()
+	return h(c)
+}
+
+// context implements transport.Context on top of the in-memory frontend
+type context struct {
+	f        *Frontend
+	sender   int64
+	chat     int64
+	lang     string
+	data     string
+	doc      *transport.Document
+	callback *transport.MessageRef
+}
+
+func (c *context) SenderID() int64               { return c.sender }
+func (c *context) ChatID() int64                 { return c.chat }
+func (c *context) LanguageCode() string          { return c.lang }
+func (c *context) Data() string                  { return c.data }
+func (c *context) Document() *transport.Document { return c.doc }
+func (c *context) Notifier() transport.Notifier  { return c.f }
+
+func (c *context) Args() []string {
+	if c.callback != nil {
+		return strings.Split(c.data, "|")
+	}
+	return strings.Fields(c.data)
+}
+
+func (c *context) Send(text string, opts ...transport.Option) error {
+	_, err := c.f.Notify(c.chat, text, opts...)
+	return err
+}
+
+func (c *context) Edit(text string, opts ...transport.Option) error {
+	if c.callback == nil {
+		return fmt.Errorf("edit outside of a callback")
+	}
+	return c.f.EditMessage(*c.callback, text, opts...)
+}
+
+func (c *context) Respond(text string) error {
+	if c.callback == nil || text == "" {
+		return nil
+	}
+	c.f.mu.Lock()
+	defer c.f.mu.Unlock()
+	c.f.messages = append(c.f.messages, Message{Ref: *c.callback, Text: text, Response: true})
+	return nil
+}
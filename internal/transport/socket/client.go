@@ -0,0 +1,38 @@
+package socket
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DefaultPath returns the socket location used when none is configured
+func DefaultPath() string {
+	if p := os.Getenv("ASB_SOCKET"); p != "" {
+		return p
+	}
+	return filepath.Join(os.TempDir(), "asb.sock")
+}
+
+// Call sends a single command to the running daemon and waits for its output
+func Call(path string, command string, args []string) (*Response, error) {
+	conn, err := net.DialTimeout("unix", path, 5*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("cannot reach ASB daemon at %s: %w", path, err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(requestTimeout))
+
+	if err := json.NewEncoder(conn).Encode(Request{Command: command, Args: args}); err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+
+	var resp Response
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	return &resp, nil
+}
@@ -0,0 +1,193 @@
+package socket
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"android-server-brain/internal/transport"
+)
+
+// requestTimeout bounds how long a single CLI request may take
+const requestTimeout = 2 * time.Minute
+
+// Request is sent by the CLI client, one JSON object per connection
+type Request struct {
+	Command string   `json:"command"`
+	Args    []string `json:"args"`
+}
+
+// Response carries the plain-text output of a command back to the client
+type Response struct {
+	Output string `json:"output"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Frontend serves the command set over a Unix socket for the local CLI.
+// Every request is executed on behalf of ownerID; access control is left
+// to the file permissions of the socket.
+type Frontend struct {
+	path     string
+	ownerID  int64
+	listener net.Listener
+
+	mu         sync.RWMutex
+	handlers   map[string]transport.HandlerFunc
+	middleware []transport.MiddlewareFunc
+}
+
+// Listen creates the socket at path, replacing a stale one if present
+func Listen(path string, ownerID int64) (*Frontend, error) {
+	if _, err := os.Lstat(path); err == nil {
+		// A live daemon would still accept connections
+		if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("socket %s is already in use", path)
+		}
+		os.Remove(path)
+	}
+
+	// Every caller is treated as the owner, so the socket must never be
+	// reachable by others, not even between creating it and a chmod
+	oldMask := syscall.Umask(0177)
+	l, err := net.Listen("unix", path)
+	syscall.Umask(oldMask)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", path, err)
+	}
+
+	return &Frontend{
+		path:     path,
+		ownerID:  ownerID,
+		listener: l,
+		handlers: make(map[string]transport.HandlerFunc),
+	}, nil
+}
+
+// Serve accepts connections until Close is called
+func (f *Frontend) Serve() {
+	for {
+		conn, err := f.listener.Accept()
+		if err != nil {
+			if !strings.Contains(err.Error(), "use of closed network connection") {
+				log.Printf("CLI socket accept failed: %v", err)
+			}
+			return
+		}
+		go f.serveConn(conn)
+	}
+}
+
+// Close stops accepting connections and removes the socket file
+func (f *Frontend) Close() error {
+	err := f.listener.Close()
+	os.Remove(f.path)
+	return err
+}
+
+func (f *Frontend) serveConn(conn net.Conn) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(requestTimeout))
+
+	var req Request
+	if err := json.NewDecoder(conn).Decode(&req); err != nil {
+		json.NewEncoder(conn).Encode(Response{Error: fmt.Sprintf("bad request: %v", err)})
+		return
+	}
+
+	command := req.Command
+	if !strings.HasPrefix(command, "/") {
+		command = "/" + command
+	}
+
+	f.mu.RLock()
+	h, ok := f.handlers[command]
+	mws := append([]transport.MiddlewareFunc(nil), f.middleware...)
+	f.mu.RUnlock()
+
+	if !ok {
+		json.NewEncoder(conn).Encode(Response{Error: fmt.Sprintf("unknown command: %s", req.Command)})
+		return
+	}
+	for i := len(mws) - 1; i >= 0; i-- {
+		h = mws[i](h)
+	}
+
+	c := &context{f: f, args: req.Args}
+	resp := Response{}
+	if err := h(c); err != nil {
+		resp.Error = err.Error()
+	}
+	resp.Output = strings.Join(c.output, "\n\n")
+
+	json.NewEncoder(conn).Encode(resp)
+}
+
+// Use adds middleware applied to every handler
+func (f *Frontend) Use(mw transport.MiddlewareFunc) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.middleware = append(f.middleware, mw)
+}
+
+// Handle registers a handler for a command
+func (f *Frontend) Handle(command string, h transport.HandlerFunc) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.handlers[command] = h
+}
+
+// HandleButton is a no-op: the CLI has no inline buttons
+func (f *Frontend) HandleButton(unique string, h transport.HandlerFunc) {}
+
+// HandleDocument is a no-op: files are copied directly on the device
+func (f *Frontend) HandleDocument(h transport.HandlerFunc) {}
+
+// Notify is not supported, the CLI only answers requests
+func (f *Frontend) Notify(chatID int64, text string, opts ...transport.Option) (transport.MessageRef, error) {
+	return transport.MessageRef{}, fmt.Errorf("cli frontend cannot send unsolicited messages")
+}
+
+// EditMessage is not supported, the CLI only answers requests
+func (f *Frontend) EditMessage(ref transport.MessageRef, text string, opts ...transport.Option) error {
+	return fmt.Errorf("cli frontend cannot edit messages")
+}
+
+// Download is not supported, the CLI does not receive files
+func (f *Frontend) Download(doc *transport.Document, localPath string) error {
+	return fmt.Errorf("cli frontend does not receive files")
+}
+
+// context collects everything a handler sends as plain text
+type context struct {
+	f      *Frontend
+	args   []string
+	output []string
+}
+
+func (c *context) SenderID() int64               { return c.f.ownerID }
+func (c *context) ChatID() int64                 { return c.f.ownerID }
+func (c *context) LanguageCode() string          { return "" }
+func (c *context) Args() []string                { return c.args }
+func (c *context) Data() string                  { return strings.Join(c.args, " ") }
+func (c *context) Document() *transport.Document { return nil }
+func (c *context) Notifier() transport.Notifier  { return c.f }
+func (c *context) Respond(text string) error     { return nil }
+
+func (c *context) Send(text string, opts ...transport.Option) error {
+	if transport.ResolveOptions(opts...).ParseMode == transport.ModeMarkdown {
+		text = transport.StripMarkdown(text)
+	}
+	c.output = append(c.output, text)
+	return nil
+}
+
+func (c *context) Edit(text string, opts ...transport.Option) error {
+	return c.Send(text, opts...)
+}
@@ -0,0 +1,59 @@
+package socket
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"android-server-brain/internal/transport"
+)
+
+func TestListenIsOwnerOnly(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "asb.sock")
+	f, err := Listen(path, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("socket mode %o, want 600", perm)
+	}
+}
+
+func TestCallRunsHandler(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "asb.sock")
+	f, err := Listen(path, 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	f.Handle("/echo", func(c transport.Context) error {
+		if c.SenderID() != 42 {
+			t.Errorf("sender %d, want the owner", c.SenderID())
+		}
+		return c.Send("*"+strings.Join(c.Args(), " ")+"*", transport.ModeMarkdown)
+	})
+	go f.Serve()
+
+	resp, err := Call(path, "echo", []string{"hello", "there"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Output != "hello there" || resp.Error != "" {
+		t.Errorf("got %+v, want plain output", resp)
+	}
+
+	resp, err = Call(path, "nope", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(resp.Error, "unknown command") {
+		t.Errorf("got %+v, want an unknown command error", resp)
+	}
+}
@@ -0,0 +1,145 @@
+package telegram
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"android-server-brain/config"
+
+	tele "gopkg.in/telebot.v3"
+)
+
+// maxUpdateSize bounds the body of a single webhook request
+const maxUpdateSize = 1 << 20
+
+// NewPoller returns the update source selected in config: a webhook when
+// enabled, long polling otherwise
+func NewPoller(cfg config.WebhookConfig) tele.Poller {
+	longPoller := &tele.LongPoller{Timeout: 10 * time.Second}
+	if !cfg.Enabled {
+		return &cleanLongPoller{LongPoller: longPoller}
+	}
+
+	secret := cfg.SecretToken
+	if secret == "" {
+		secret = randomToken()
+	}
+
+	return &WebhookPoller{
+		cfg:      cfg,
+		secret:   secret,
+		fallback: longPoller,
+	}
+}
+
+// cleanLongPoller drops a webhook left over from a previous run, since
+// Telegram refuses getUpdates while one is registered
+type cleanLongPoller struct {
+	*tele.LongPoller
+}
+
+func (p *cleanLongPoller) Poll(b *tele.Bot, dest chan tele.Update, stop chan struct{}) {
+	if err := b.RemoveWebhook(); err != nil {
+		log.Printf("Failed to remove stale webhook: %v", err)
+	}
+	p.LongPoller.Poll(b, dest, stop)
+}
+
+// WebhookPoller receives updates over HTTP and falls back to long polling
+// when the webhook cannot be registered
+type WebhookPoller struct {
+	cfg      config.WebhookConfig
+	secret   string
+	fallback *tele.LongPoller
+	dest     chan tele.Update
+}
+
+// Poll registers the webhook and serves it until stop is closed
+func (p *WebhookPoller) Poll(b *tele.Bot, dest chan tele.Update, stop chan struct{}) {
+	hook := &tele.Webhook{
+		SecretToken: p.secret,
+		Endpoint: &tele.WebhookEndpoint{
+			PublicURL: p.cfg.PublicURL,
+		},
+	}
+	// A self-signed certificate has to be uploaded to Telegram
+	if p.cfg.CertFile != "" {
+		hook.Endpoint.Cert = p.cfg.CertFile
+	}
+
+	if err := b.SetWebhook(hook); err != nil {
+		log.Printf("Webhook registration failed, falling back to long polling: %v", err)
+		(&cleanLongPoller{LongPoller: p.fallback}).Poll(b, dest, stop)
+		return
+	}
+
+	p.dest = dest
+	server := &http.Server{
+		Addr:              p.cfg.Listen,
+		Handler:           p,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-stop
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(ctx)
+	}()
+
+	log.Printf("Webhook listening on %s for %s", p.cfg.Listen, p.cfg.PublicURL)
+
+	var err error
+	if p.cfg.CertFile != "" && p.cfg.KeyFile != "" {
+		err = server.ListenAndServeTLS(p.cfg.CertFile, p.cfg.KeyFile)
+	} else {
+		err = server.ListenAndServe()
+	}
+	if err != nil && err != http.ErrServerClosed {
+		log.Printf("Webhook listener failed, falling back to long polling: %v", err)
+		if err := b.RemoveWebhook(); err != nil {
+			log.Printf("Failed to remove webhook: %v", err)
+		}
+		p.fallback.Poll(b, dest, stop)
+	}
+}
+
+// ServeHTTP validates the secret token and forwards the update to the bot
+func (p *WebhookPoller) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	token := r.Header.Get("X-Telegram-Bot-Api-Secret-Token")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(p.secret)) != 1 {
+		log.Printf("Rejected webhook request from %s: invalid secret token", r.RemoteAddr)
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	var update tele.Update
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxUpdateSize)).Decode(&update); err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	p.dest <- update
+	w.WriteHeader(http.StatusOK)
+}
+
+// randomToken generates a secret accepted by Telegram ([A-Za-z0-9_-])
+func randomToken() string {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		log.Fatalf("Failed to generate webhook secret: %v", err)
+	}
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,219 @@
+package telegram
+
+import (
+	"fmt"
+	"sync"
+
+	"android-server-brain/internal/transport"
+
+	tele "gopkg.in/telebot.v3"
+)
+
+// Frontend adapts a telebot bot to the transport.Frontend interface
+type Frontend struct {
+	bot *tele.Bot
+
+	mu         sync.RWMutex
+	middleware []transport.MiddlewareFunc
+}
+
+// New wraps an existing telebot instance
+func New(b *tele.Bot) *Frontend {
+	return &Frontend{bot: b}
+}
+
+// Bot returns the underlying telebot instance
+func (f *Frontend) Bot() *tele.Bot {
+	return f.bot
+}
+
+// Use adds middleware applied to every handler, including ones registered earlier
+func (f *Frontend) Use(mw transport.MiddlewareFunc) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.middleware = append(f.middleware, mw)
+}
+
+// Handle registers a handler for a text command
+func (f *Frontend) Handle(command string, h transport.HandlerFunc) {
+	f.bot.Handle(command, f.wrap(h))
+}
+
+// HandleButton registers a handler for inline buttons with the given unique ID
+func (f *Frontend) HandleButton(unique string, h transport.HandlerFunc) {
+	f.bot.Handle("\f"+unique, f.wrap(h))
+}
+
+// HandleDocument registers a handler for incoming files
+func (f *Frontend) HandleDocument(h transport.HandlerFunc) {
+	f.bot.Handle(tele.OnDocument, f.wrap(h))
+}
+
+// Notify sends a message to the given chat
+func (f *Frontend) Notify(chatID int64, text string, opts ...transport.Option) (transport.MessageRef, error) {
+	msg, err := f.bot.Send(tele.ChatID(chatID), text, sendOptions(opts)...)
+	if err != nil {
+		return transport.MessageRef{}, err
+	}
+	return transport.MessageRef{ChatID: msg.Chat.ID, MessageID: msg.ID}, nil
+}
+
+// EditMessage replaces the text of a previously sent message
+func (f *Frontend) EditMessage(ref transport.MessageRef, text string, opts ...transport.Option) error {
+	_, err := f.bot.Edit(storedMessage(ref), text, sendOptions(opts)...)
+	return err
+}
+
+// Download fetches a document from Telegram servers into localPath
+func (f *Frontend) Download(doc *transport.Document, localPath string) error {
+	file, err := f.bot.FileByID(doc.FileID)
+	if err != nil {
+		return fmt.Errorf("failed to get file by ID: %v", err)
+	}
+	return f.bot.Download(&file, localPath)
+}
+
+// SetCommands publishes the command menu shown to a single chat
+func (f *Frontend) SetCommands(chatID int64, commands []transport.CommandInfo) error {
+	cmds := make([]tele.Command, 0, len(commands))
+	for _, c := range commands {
+		cmds = append(cmds, tele.Command{Text: c.Name, Description: c.Description})
+	}
+	return f.bot.SetCommands(cmds, tele.CommandScope{Type: tele.CommandScopeChat, ChatID: chatID})
+}
+
+// Pin pins a message silently
+func (f *Frontend) Pin(ref transport.MessageRef) error {
+	return f.bot.Pin(storedMessage(ref), tele.Silent)
+}
+
+// Unpin unpins a message
+func (f *Frontend) Unpin(ref transport.MessageRef) error {
+	return f.bot.Unpin(tele.ChatID(ref.ChatID), ref.MessageID)
+}
+
+// Pinned returns the most recently pinned message of a chat
+func (f *Frontend) Pinned(chatID int64) (transport.MessageRef, bool, error) {
+	chat, err := f.bot.ChatByID(chatID)
+	if err != nil {
+		return transport.MessageRef{}, false, err
+	}
+	if chat.PinnedMessage == nil {
+		return transport.MessageRef{}, false, nil
+	}
+	return transport.MessageRef{ChatID: chatID, MessageID: chat.PinnedMessage.ID}, true, nil
+}
+
+func storedMessage(ref transport.MessageRef) tele.StoredMessage {
+	return tele.StoredMessage{
+		MessageID: fmt.Sprint(ref.MessageID),
+		ChatID:    ref.ChatID,
+	}
+}
+
+func (f *Frontend) wrap(h transport.HandlerFunc) tele.HandlerFunc {
+	return func(c tele.Context) error {
+		f.mu.RLock()
+		handler := h
+		for i := len(f.middleware) - 1; i >= 0; i-- {
+			handler = f.middleware[i](handler)
+		}
+		f.mu.RUnlock()
+
+		return handler(&updateContext{c: c, f: f})
+	}
+}
+
+// sendOptions converts transport options into telebot send options
+func sendOptions(opts []transport.Option) []interface{} {
+	o := transport.ResolveOptions(opts...)
+
+	var out []interface{}
+	if o.ParseMode == transport.ModeMarkdown {
+		out = append(out, tele.ModeMarkdown)
+	}
+	if o.Keyboard != nil {
+		markup := &tele.ReplyMarkup{}
+		rows := make([]tele.Row, 0, len(o.Keyboard))
+		for _, row := range o.Keyboard {
+			btns := make([]tele.Btn, 0, len(row))
+			for _, b := range row {
+				btns = append(btns, markup.Data(b.Text, b.Unique, b.Data))
+			}
+			rows = append(rows, markup.Row(btns...))
+		}
+		markup.Inline(rows...)
+		out = append(out, markup)
+	}
+	return out
+}
+
+// updateContext adapts tele.Context to transport.Context
+type updateContext struct {
+	c tele.Context
+	f *Frontend
+}
+
+func (c *updateContext) SenderID() int64 {
+	if s := c.c.Sender(); s != nil {
+		return s.ID
+	}
+	return 0
+}
+
+func (c *updateContext) LanguageCode() string {
+	if s := c.c.Sender(); s != nil {
+		return s.LanguageCode
+	}
+	return ""
+}
+
+func (c *updateContext) ChatID() int64 {
+	if chat := c.c.Chat(); chat != nil {
+		return chat.ID
+	}
+	if cb := c.c.Callback(); cb != nil && cb.Message != nil && cb.Message.Chat != nil {
+		return cb.Message.Chat.ID
+	}
+	return c.SenderID()
+}
+
+func (c *updateContext) Args() []string {
+	// telebot splits callback data by "|", commands by whitespace
+	return c.c.Args()
+}
+
+func (c *updateContext) Data() string {
+	return c.c.Data()
+}
+
+func (c *updateContext) Document() *transport.Document {
+	msg := c.c.Message()
+	if msg == nil || msg.Document == nil {
+		return nil
+	}
+	return &transport.Document{
+		FileID:   msg.Document.FileID,
+		FileName: msg.Document.FileName,
+		Size:     msg.Document.FileSize,
+	}
+}
+
+func (c *updateContext) Send(text string, opts ...transport.Option) error {
+	return c.c.Send(text, sendOptions(opts)...)
+}
+
+func (c *updateContext) Edit(text string, opts ...transport.Option) error {
+	return c.c.Edit(text, sendOptions(opts)...)
+}
+
+func (c *updateContext) Respond(text string) error {
+	if c.c.Callback() == nil {
+		return nil
+	}
+	return c.c.Respond(&tele.CallbackResponse{Text: text})
+}
+
+func (c *updateContext) Notifier() transport.Notifier {
+	return c.f
+}
@@ -0,0 +1,146 @@
+package transport
+
+// Transport abstracts the chat frontend (Telegram, local tools, tests) away
+// from the command logic. Handlers only talk to the interfaces defined here,
+// so the same code can be driven by any adapter.
+
+// ParseMode selects how the text of a message is rendered by the frontend
+type ParseMode int
+
+const (
+	// ModePlain sends the text as-is
+	ModePlain ParseMode = iota
+	// ModeMarkdown renders the text as (legacy) Telegram Markdown
+	ModeMarkdown
+)
+
+// Button is a single inline button. Unique identifies the handler that
+// receives the press, Data is passed to it as the callback payload.
+type Button struct {
+	Text   string
+	Unique string
+	Data   string
+}
+
+// Keyboard is a grid of inline buttons attached to a message
+type Keyboard [][]Button
+
+// Row is a helper for building a keyboard row
+func Row(buttons ...Button) []Button {
+	return buttons
+}
+
+// Option customizes an outgoing message
+type Option interface {
+	apply(*SendOptions)
+}
+
+// SendOptions is the resolved set of options for an outgoing message
+type SendOptions struct {
+	ParseMode ParseMode
+	Keyboard  Keyboard
+}
+
+func (m ParseMode) apply(o *SendOptions) { o.ParseMode = m }
+func (k Keyboard) apply(o *SendOptions)  { o.Keyboard = k }
+
+// ResolveOptions folds options into a SendOptions value. Adapters use it to
+// translate options into their native representation.
+func ResolveOptions(opts ...Option) SendOptions {
+	var o SendOptions
+	for _, opt := range opts {
+		if opt != nil {
+			opt.apply(&o)
+		}
+	}
+	return o
+}
+
+// MessageRef identifies a sent message so it can be edited later
+type MessageRef struct {
+	ChatID    int64
+	MessageID int
+}
+
+// Document is a file received from a user
+type Document struct {
+	FileID   string
+	FileName string
+	Size     int64
+}
+
+// Context is the per-update view a handler works with
+type Context interface {
+	// SenderID returns the ID of the user who triggered the update
+	SenderID() int64
+	// ChatID returns the chat the update came from
+	ChatID() int64
+	// LanguageCode returns the client language of the sender, if known
+	LanguageCode() string
+	// Args returns the command arguments split by whitespace
+	Args() []string
+	// Data returns the raw command payload or callback data
+	Data() string
+	// Document returns the attached file, if any
+	Document() *Document
+	// Send sends a new message to the chat of the update
+	Send(text string, opts ...Option) error
+	// Edit replaces the message the callback button belongs to
+	Edit(text string, opts ...Option) error
+	// Respond answers a callback query with a short notification
+	Respond(text string) error
+	// Notifier gives access to the frontend for out-of-band messages
+	Notifier() Notifier
+}
+
+// HandlerFunc handles a single update
+type HandlerFunc func(Context) error
+
+// MiddlewareFunc wraps a handler
+type MiddlewareFunc func(HandlerFunc) HandlerFunc
+
+// Notifier delivers messages that are not replies to an update,
+// such as watchdog alerts
+type Notifier interface {
+	Notify(chatID int64, text string, opts ...Option) (MessageRef, error)
+	EditMessage(ref MessageRef, text string, opts ...Option) error
+}
+
+// Fetcher downloads files that users send to the bot
+type Fetcher interface {
+	Download(doc *Document, localPath string) error
+}
+
+// Frontend is a chat frontend that dispatches updates to handlers
+type Frontend interface {
+	Notifier
+	Fetcher
+
+	// Handle registers a handler for a command such as "/status"
+	Handle(command string, h HandlerFunc)
+	// HandleButton registers a handler for inline buttons with the given unique ID
+	HandleButton(unique string, h HandlerFunc)
+	// HandleDocument registers a handler for incoming files
+	HandleDocument(h HandlerFunc)
+	// Use adds middleware applied to every handler
+	Use(mw MiddlewareFunc)
+}
+
+// CommandInfo describes a command shown in the client's command menu
+type CommandInfo struct {
+	Name        string
+	Description string
+}
+
+// CommandMenu is implemented by frontends that can show a per-chat command menu
+type CommandMenu interface {
+	SetCommands(chatID int64, commands []CommandInfo) error
+}
+
+// Pinner is implemented by frontends that can pin messages in a chat
+type Pinner interface {
+	Pin(ref MessageRef) error
+	Unpin(ref MessageRef) error
+	// Pinned returns the pinned message of a chat, if any
+	Pinned(chatID int64) (MessageRef, bool, error)
+}
@@ -1,18 +1,32 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"fmt"
 	"log"
+	"os"
 	"os/exec"
-	"time"
+	"os/signal"
+	"syscall"
 
 	"android-server-brain/config"
 	"android-server-brain/internal/bot"
+	"android-server-brain/internal/i18n"
 	"android-server-brain/internal/system"
+	"android-server-brain/internal/transport"
+	"android-server-brain/internal/transport/socket"
+	"android-server-brain/internal/transport/telegram"
 
 	tele "gopkg.in/telebot.v3"
 )
 
 func main() {
+	// Subcommand mode: `asb status` talks to the running daemon
+	if len(os.Args) > 1 {
+		os.Exit(runClient(os.Args[1:]))
+	}
+
 	// Initialize configuration and directories
 	cfg := config.LoadConfig()
 
@@ -21,10 +35,10 @@ func main() {
 		log.Println("Warning: termux-api not found. Some monitoring features will be disabled.")
 	}
 
-	// Bot settings
+	// Bot settings: webhook or long polling depending on config
 	pref := tele.Settings{
 		Token:  cfg.TelegramToken,
-		Poller: &tele.LongPoller{Timeout: 10 * time.Second},
+		Poller: telegram.NewPoller(cfg.Webhook),
 	}
 
 	b, err := tele.NewBot(pref)
@@ -32,24 +46,112 @@ func main() {
 		log.Fatal(err)
 	}
 
-	// Middleware: restrict access to AdminID only
-	b.Use(func(next tele.HandlerFunc) tele.HandlerFunc {
-		return func(c tele.Context) error {
-			if c.Sender().ID != cfg.AdminID {
+	frontend := telegram.New(b)
+
+	// Middleware: restrict access to configured users only
+	frontend.Use(func(next transport.HandlerFunc) transport.HandlerFunc {
+		return func(c transport.Context) error {
+			if _, ok := cfg.RoleOf(c.SenderID()); !ok {
 				return nil // Ignore unauthorized users
 			}
 			return next(c)
 		}
 	})
 
-	// Start watchdog for battery monitoring
-	watchdog := system.NewWatchdog(b, cfg, 10*time.Minute)
-	watchdog.Start()
+	// Per-user language preferences
+	langs := i18n.NewStore(cfg.StatePath("languages.json"))
+
+	// Stop cleanly on SIGINT/SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Start the watchdog health checks
+	watchdog := system.NewWatchdog(frontend, cfg, langs, cfg.Watchdog.Interval())
+	watchdog.Start(ctx)
+	system.NewDigest(frontend, cfg, langs, watchdog).Start(ctx)
+	heartbeat := system.NewHeartbeat(cfg.Heartbeat, watchdog)
+	heartbeat.Start(ctx)
+
+	// Scheduled tasks from config.json and /schedule
+	scheduler, err := system.NewScheduler(frontend, cfg, langs, watchdog, heartbeat)
+	if err != nil {
+		log.Fatalf("schedule: %v", err)
+	}
+	scheduler.Start(ctx)
+
+	// Programs supervised by ASB itself, listed before runit services
+	supervisor := system.NewSupervisor(cfg, watchdog)
+	system.RegisterServiceBackend(supervisor)
+	supervisor.StartAll()
+
+	// Saved command aliases, shared by Telegram and the CLI
+	aliases := bot.NewAliasStore(cfg.StatePath("aliases.json"))
+
+	// Setup routes and the per-user command menu
+	registry := bot.RegisterHandlers(frontend, cfg, langs, watchdog, scheduler, aliases)
+	registry.PublishMenu()
+	registry.StartDashboard(ctx)
 
-	// Setup routes
-	bot.RegisterHandlers(b, cfg, watchdog)
+	// Local CLI access over a Unix socket
+	socketPath := cfg.SocketPath
+	if socketPath == "" {
+		socketPath = socket.DefaultPath()
+	}
+	cli, err := socket.Listen(socketPath, cfg.AdminID)
+	if err != nil {
+		log.Printf("Warning: CLI socket disabled: %v", err)
+	} else {
+		defer cli.Close()
+		bot.RegisterHandlers(cli, cfg, langs, watchdog, scheduler, aliases)
+		go cli.Serve()
+		log.Printf("CLI socket listening on %s", socketPath)
+	}
+
+	go func() {
+		<-ctx.Done()
+		log.Printf("Shutting down...")
+		watchdog.Stop()
+		supervisor.Shutdown()
+		b.Stop()
+	}()
 
 	log.Printf("ASB Started: Admin ID %d", cfg.AdminID)
-	log.Printf("Watchdog monitoring started with 10-minute intervals")
 	b.Start()
 }
+
+// runClient sends a single command to the running daemon and prints the result
+func runClient(args []string) int {
+	fs := flag.NewFlagSet("asb", flag.ExitOnError)
+	// Same lookup as the daemon: socket_path from config.json, then the default
+	defaultSocket := config.ReadSocketPath()
+	if defaultSocket == "" {
+		defaultSocket = socket.DefaultPath()
+	}
+	socketPath := fs.String("socket", defaultSocket, "path to the ASB daemon socket")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: asb [-socket path] <command> [args...]")
+		fmt.Fprintln(fs.Output(), "Example: asb status, asb watchdog, asb exec uptime")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	resp, err := socket.Call(*socketPath, fs.Arg(0), fs.Args()[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	if resp.Output != "" {
+		fmt.Println(resp.Output)
+	}
+	if resp.Error != "" {
+		fmt.Fprintln(os.Stderr, "error:", resp.Error)
+		return 1
+	}
+	return 0
+}